@@ -0,0 +1,65 @@
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// Diagnostic is an error tied to a location in a source file.
+type Diagnostic struct {
+	Position shared.Position
+	Err      error
+}
+
+// Errorf creates a Diagnostic at the given position. It supports %w like fmt.Errorf.
+func Errorf(position shared.Position, format string, args ...any) error {
+	return &Diagnostic{
+		Position: position,
+		Err:      fmt.Errorf(format, args...),
+	}
+}
+
+// Error renders the diagnostic as "file:line:column: message".
+func (d *Diagnostic) Error() string {
+	if !d.Position.IsValid() {
+		return d.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", d.Position, d.Err)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// PositionOf returns the position of the first Diagnostic in the error chain,
+// or the zero Position if there is none.
+func PositionOf(err error) shared.Position {
+	var diagnostic *Diagnostic
+	if errors.As(err, &diagnostic) {
+		return diagnostic.Position
+	}
+	return shared.Position{}
+}
+
+// List is a set of diagnostics reported together, such as every syntax error in a file.
+type List []*Diagnostic
+
+// Error renders every diagnostic on its own line.
+func (l List) Error() string {
+	messages := make([]string, len(l))
+	for i, diagnostic := range l {
+		messages[i] = diagnostic.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+func (l List) Unwrap() []error {
+	errs := make([]error, len(l))
+	for i, diagnostic := range l {
+		errs[i] = diagnostic
+	}
+	return errs
+}
@@ -0,0 +1,74 @@
+package diagnostics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// Render formats an error for display.
+// If the error carries a Diagnostic, the offending source line is appended with a caret under the column.
+// A List is rendered one diagnostic after another.
+func Render(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var list List
+	if errors.As(err, &list) {
+		rendered := make([]string, len(list))
+		for i, diagnostic := range list {
+			rendered[i] = Render(diagnostic)
+		}
+		return strings.Join(rendered, "\n")
+	}
+
+	var diagnostic *Diagnostic
+	if !errors.As(err, &diagnostic) {
+		return err.Error()
+	}
+
+	snippet := renderSnippet(diagnostic.Position)
+	if snippet == "" {
+		return err.Error()
+	}
+
+	return err.Error() + "\n" + snippet
+}
+
+// renderSnippet returns the source line of the position and a caret line, or an empty string if unavailable.
+func renderSnippet(position shared.Position) string {
+	if !position.IsValid() || position.File == "" {
+		return ""
+	}
+
+	content, err := os.ReadFile(position.File)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n")
+	if position.Line > len(lines) {
+		return ""
+	}
+
+	sourceLine := []rune(lines[position.Line-1])
+	gutter := fmt.Sprintf("%d", position.Line)
+	padding := strings.Repeat(" ", len(gutter))
+
+	var caret strings.Builder
+	for i := 0; i < position.Column-1 && i < len(sourceLine); i++ {
+		// Keep tabs so the caret lines up with the source line.
+		if sourceLine[i] == '\t' {
+			caret.WriteRune('\t')
+		} else {
+			caret.WriteRune(' ')
+		}
+	}
+	caret.WriteRune('^')
+
+	return fmt.Sprintf(" %s | %s\n %s | %s", gutter, string(sourceLine), padding, caret.String())
+}
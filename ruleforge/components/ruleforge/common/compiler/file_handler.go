@@ -16,13 +16,28 @@ type FileHandler[T shared.TokenTypeConstraint] struct {
 	parser *parsing.Parser[T]
 }
 
-func NewFileHandler[T shared.TokenTypeConstraint](reader io.Reader, lexingRules []rules.LexingRuleInterface[T], parsingRules []shared3.ParsingRuleInterface[T], ignoreTokenType T) *FileHandler[T] {
-	lexer := lexing.NewLexer[T](reader, lexingRules)
+// NewFileHandler reads the input and prepares its lexer and parser.
+// It returns a lexing.LexError if the input cannot be read, or a parsing.ParseError if the parser cannot be built.
+func NewFileHandler[T shared.TokenTypeConstraint](reader io.Reader, lexingRules []rules.LexingRuleInterface[T], parsingRules []shared3.ParsingRuleInterface[T], ignoreTokenType T) (*FileHandler[T], error) {
+	lexer, err := lexing.NewLexer[T](reader, lexingRules)
+	if err != nil {
+		return nil, err
+	}
+
+	parser, err := parsing.NewParser[T](lexer, parsingRules, ignoreTokenType)
+	if err != nil {
+		return nil, err
+	}
 
 	return &FileHandler[T]{
 		lexer:  lexer,
-		parser: parsing.NewParser[T](lexer, parsingRules, ignoreTokenType),
-	}
+		parser: parser,
+	}, nil
+}
+
+// EnableRecovery makes Parse report every syntax error instead of stopping at the first one.
+func (fh *FileHandler[T]) EnableRecovery(options parsing.RecoveryOptions[T]) {
+	fh.parser.EnableRecovery(options)
 }
 
 func (fh *FileHandler[T]) Lex() ([]*shared.Token[T], error) {
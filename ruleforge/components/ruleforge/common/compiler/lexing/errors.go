@@ -0,0 +1,24 @@
+package lexing
+
+import (
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// LexError is returned when the input stream cannot be read or tokenized.
+type LexError struct {
+	Err error
+}
+
+func (e *LexError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LexError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the location in the source at which lexing failed.
+func (e *LexError) Position() shared.Position {
+	return diagnostics.PositionOf(e.Err)
+}
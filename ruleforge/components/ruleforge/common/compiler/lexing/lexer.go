@@ -3,6 +3,7 @@ package lexing
 import (
 	"io"
 
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/rules"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/scanning"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
@@ -15,25 +16,30 @@ type Lexer[T shared.TokenTypeConstraint] struct {
 }
 
 // NewLexer creates a new lexer for the given input stream.
-func NewLexer[T shared.TokenTypeConstraint](reader io.Reader, lexingRules []rules.LexingRuleInterface[T]) *Lexer[T] {
-	scanner := scanning.NewScanner(reader)
+// It returns a LexError if the input stream cannot be read.
+func NewLexer[T shared.TokenTypeConstraint](reader io.Reader, lexingRules []rules.LexingRuleInterface[T]) (*Lexer[T], error) {
+	scanner, err := scanning.NewScanner(reader)
+	if err != nil {
+		return nil, &LexError{Err: err}
+	}
 	ruleset := NewRuleset[T](lexingRules)
 
 	return &Lexer[T]{
 		scanner: scanner,
 		ruleSet: ruleset,
-	}
+	}, nil
 }
 
 // GetToken returns the next token from the input stream.
-func (l *Lexer[T]) GetToken() *shared.Token[T] {
+// It returns a nil token once the input stream is exhausted, and a LexError if no rule can tokenize the input.
+func (l *Lexer[T]) GetToken() (*shared.Token[T], error) {
 	matchingRule, err := l.getMatchingRule()
 	if err != nil {
-		panic(err)
+		return nil, &LexError{Err: diagnostics.Errorf(l.scanner.Location(), "%w", err)}
 	}
 
 	if matchingRule == nil {
-		panic("no matching rule found")
+		return nil, &LexError{Err: diagnostics.Errorf(l.scanner.Location(), "no matching rule found")}
 	}
 
 	return l.extractToken(matchingRule)
@@ -49,22 +55,27 @@ func (l *Lexer[T]) getMatchingRule() (rules.LexingRuleInterface[T], error) {
 }
 
 // extractToken extracts the token from the matched rule.
-func (l *Lexer[T]) extractToken(rule rules.LexingRuleInterface[T]) *shared.Token[T] {
+func (l *Lexer[T]) extractToken(rule rules.LexingRuleInterface[T]) (*shared.Token[T], error) {
+	position := l.scanner.Location()
+
 	t, err, consumedN := rule.ExtractToken(l.scanner)
 	if err != nil {
-		panic(err)
+		return nil, &LexError{Err: diagnostics.Errorf(position, "%w", err)}
+	}
+	if t != nil {
+		t.Position = position
 	}
 
 	_, err = l.scanner.Consume(consumedN)
 	if err != nil {
 		if err == io.EOF {
-			return nil
+			return nil, nil
 		}
 
-		panic(err)
+		return nil, &LexError{Err: diagnostics.Errorf(position, "%w", err)}
 	}
 
-	return t
+	return t, nil
 }
 
 // GetTokens returns all tokens from the input stream.
@@ -72,7 +83,10 @@ func (l *Lexer[T]) GetTokens() ([]*shared.Token[T], error) {
 	tokens := make([]*shared.Token[T], 0)
 
 	for {
-		token := l.GetToken()
+		token, err := l.GetToken()
+		if err != nil {
+			return nil, err
+		}
 
 		if token == nil {
 			break
@@ -3,8 +3,8 @@ package lexing
 import "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 
 type LexerInterface[T shared.TokenTypeConstraint] interface {
-	// GetToken returns the next token from the input stream.
-	GetToken() *shared.Token[T]
+	// GetToken returns the next token from the input stream, or nil once it is exhausted.
+	GetToken() (*shared.Token[T], error)
 
 	// GetTokens returns all tokens from the input stream.
 	GetTokens() ([]*shared.Token[T], error)
@@ -2,33 +2,32 @@ package special
 
 import (
 	"fmt"
-	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/internal/util"
+
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/rules"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/scanning"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 )
 
-// NewQuotedValueRule creates a rule that recognizes identifiers wrapped in double-quotes.
+// NewQuotedValueRule creates a rule that recognizes string literals wrapped in double-quotes.
 func NewQuotedValueRule[T shared.TokenTypeConstraint](
 	symbol string,
 	tokenType T,
 	includeQuotes bool,
-	isValidCharacterRule rules.LexingRuleInterface[T],
 ) rules.LexingRuleInterface[T] {
 	return &QuotedValueRule[T]{
-		SymbolString:         symbol,
-		TokenType:            tokenType,
-		IncludeQuotes:        includeQuotes,
-		IsValidCharacterRule: isValidCharacterRule,
+		SymbolString:  symbol,
+		TokenType:     tokenType,
+		IncludeQuotes: includeQuotes,
 	}
 }
 
-// QuotedValueRule implements a rule for matching values enclosed in double quotes.
+// QuotedValueRule implements a rule for matching string literals enclosed in double quotes.
+// A literal can hold any character except a line break; `\"` stands for a quote and `\\` for a backslash.
+// The token holds the literal with its escapes resolved.
 type QuotedValueRule[T shared.TokenTypeConstraint] struct {
-	SymbolString         string
-	TokenType            T
-	IncludeQuotes        bool
-	IsValidCharacterRule rules.LexingRuleInterface[T]
+	SymbolString  string
+	TokenType     T
+	IncludeQuotes bool
 }
 
 func (q *QuotedValueRule[T]) Symbol() string {
@@ -46,45 +45,50 @@ func (q *QuotedValueRule[T]) IsMatch(scanner scanning.PeekInterface) bool {
 func (q *QuotedValueRule[T]) ExtractToken(
 	scanner scanning.PeekInterface,
 ) (*shared.Token[T], error, int) {
-	runes, err := q.scanQuoted(scanner)
+	value, consumed, err := q.scanQuoted(scanner)
 	if err != nil {
 		return &shared.Token[T]{}, err, 0
 	}
 
 	// Decide what text to emit based on the IncludeQuotes flag.
-	var emit []rune
-	if q.IncludeQuotes || len(runes) < 2 {
-		emit = runes
-	} else {
-		// Strip the opening and closing quotes.
-		emit = runes[1 : len(runes)-1]
+	if q.IncludeQuotes {
+		value = append(append([]rune{'"'}, value...), '"')
 	}
 
 	// Build and return the token.
 	tok := shared.Token[T]{
 		Type:  q.TokenType,
-		Value: []byte(string(emit)),
+		Value: []byte(string(value)),
 	}
-	return &tok, nil, len(runes)
+	return &tok, nil, consumed
 }
 
-// scanQuoted handles the logic of scanning through the string, handling escape characters.
-func (q *QuotedValueRule[T]) scanQuoted(scanner scanning.PeekInterface) ([]rune, error) {
-	content := []rune{scanner.Current()}
+// scanQuoted scans up to the closing quote and returns the content with its escapes resolved,
+// together with the number of runes consumed, quotes included.
+func (q *QuotedValueRule[T]) scanQuoted(scanner scanning.PeekInterface) ([]rune, int, error) {
+	var value []rune
 	peekIndex := 1
 	isEscaped := false
 
 	for {
 		peekedRunes, err := scanner.Peek(peekIndex)
 		if err != nil {
-			return nil, fmt.Errorf("unterminated quoted value starting at position %d", scanner.Position())
+			return nil, 0, fmt.Errorf("unterminated quoted value, the closing quote is missing")
 		}
 
 		ch := peekedRunes[len(peekedRunes)-1]
-		content = append(content, ch)
 		peekIndex++
 
+		// A line break can only be part of a value by mistake, mostly a missing closing quote.
+		if ch == '\n' || ch == '\r' {
+			return nil, 0, fmt.Errorf("unterminated quoted value, the closing quote is missing before the end of the line")
+		}
+
 		if isEscaped {
+			if ch != '"' && ch != '\\' {
+				return nil, 0, fmt.Errorf("invalid escape \\%c in quoted value, only \\\" and \\\\ are allowed", ch)
+			}
+			value = append(value, ch)
 			isEscaped = false
 			continue
 		}
@@ -95,14 +99,9 @@ func (q *QuotedValueRule[T]) scanQuoted(scanner scanning.PeekInterface) ([]rune,
 		}
 
 		if ch == '"' {
-			break
+			return value, peekIndex, nil
 		}
 
-		// For normal characters, validate them using the provided rule.
-		stub := &util.SingleRuneScanner{R: ch}
-		if !q.IsValidCharacterRule.IsMatch(stub) {
-			return nil, fmt.Errorf("invalid character %q in quoted value", ch)
-		}
+		value = append(value, ch)
 	}
-	return content, nil
 }
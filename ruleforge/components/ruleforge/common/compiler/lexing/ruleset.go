@@ -32,5 +32,5 @@ func (rs *Ruleset[T]) GetMatchingRule(scanner scanning.PeekInterface) (rules.Lex
 		}
 	}
 
-	return nil, fmt.Errorf("no matching rule found\n")
+	return nil, fmt.Errorf("no matching rule found for %q", scanner.Current())
 }
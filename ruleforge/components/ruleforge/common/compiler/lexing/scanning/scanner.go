@@ -3,6 +3,8 @@ package scanning
 import (
 	"fmt"
 	"io"
+	"sort"
+	"unicode/utf8"
 
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 )
@@ -11,24 +13,51 @@ import (
 type Scanner struct {
 	runes        []rune
 	currentIndex int
+
+	file       string
+	lineStarts []int
+}
+
+// namedReader is implemented by readers that know their origin, such as *os.File.
+type namedReader interface {
+	Name() string
 }
 
 // NewScanner creates a new Scanner with the given input stream.
-func NewScanner(reader io.Reader) *Scanner {
+// If the reader exposes a Name (e.g. *os.File), it is used as the file of all positions.
+func NewScanner(reader io.Reader) (*Scanner, error) {
 	runes, err := shared.ReaderToRunes(reader)
 
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("reading input: %w", err)
 	}
 
 	runes = append(runes, '\n') // Add newline character to the end of the runes slice...
 	// Necessary to ensure the last character is processed correctly.
 	// No idea why this is, but it works. Solve later.
 
+	file := ""
+	if named, ok := reader.(namedReader); ok {
+		file = named.Name()
+	}
+
 	return &Scanner{
 		runes:        runes,
 		currentIndex: 0,
+		file:         file,
+		lineStarts:   computeLineStarts(runes),
+	}, nil
+}
+
+// computeLineStarts returns the rune index at which every line begins.
+func computeLineStarts(runes []rune) []int {
+	lineStarts := []int{0}
+	for i, r := range runes {
+		if r == '\n' && i+1 < len(runes) {
+			lineStarts = append(lineStarts, i+1)
+		}
 	}
+	return lineStarts
 }
 
 // Peek returns the next n runes without advancing the scanner's index.
@@ -45,6 +74,19 @@ func (s *Scanner) Position() int {
 	return s.currentIndex
 }
 
+// Location returns the file, line and column of the current rune.
+func (s *Scanner) Location() shared.Position {
+	line := sort.Search(len(s.lineStarts), func(i int) bool {
+		return s.lineStarts[i] > s.currentIndex
+	}) - 1
+
+	return shared.Position{
+		File:   s.file,
+		Line:   line + 1,
+		Column: s.currentIndex - s.lineStarts[line] + 1,
+	}
+}
+
 // Consume returns the next n runes and advances the scanner's index.
 func (s *Scanner) Consume(n int) ([]rune, error) {
 	runes, err := s.Peek(n)
@@ -75,9 +117,10 @@ func (s *Scanner) Reset() {
 }
 
 // Current returns the current rune.
+// It returns utf8.RuneError if the index is out of range, which Consume and Pushback prevent.
 func (s *Scanner) Current() rune {
-	if s.currentIndex >= len(s.runes) {
-		panic("Lexer index out of range") // Obviously, this should never happen.
+	if s.currentIndex < 0 || s.currentIndex >= len(s.runes) {
+		return utf8.RuneError
 	}
 
 	return s.runes[s.currentIndex]
@@ -1,5 +1,7 @@
 package scanning
 
+import "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+
 type ScannerInterface interface {
 	Peek(n int) ([]rune, error)
 	Consume(n int) ([]rune, error)
@@ -7,6 +9,7 @@ type ScannerInterface interface {
 	Reset()
 	Current() rune
 	Position() int
+	Location() shared.Position
 }
 
 type PeekInterface interface {
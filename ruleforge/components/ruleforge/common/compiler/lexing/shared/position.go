@@ -0,0 +1,29 @@
+package shared
+
+import "fmt"
+
+// Position is a location in a source file.
+// Line and Column are 1-based; Column counts runes, not bytes.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// IsValid reports whether the position points to an actual location.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String renders the position as "file:line:column", omitting the file when unknown.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return p.File
+	}
+
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
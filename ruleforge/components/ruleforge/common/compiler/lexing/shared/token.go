@@ -7,8 +7,9 @@ import (
 
 // Token represents a lexical token
 type Token[T TokenTypeConstraint] struct {
-	Type  T
-	Value []byte
+	Type     T
+	Value    []byte
+	Position Position
 }
 
 func (t Token[T]) Equals(other Token[T]) bool {
@@ -0,0 +1,25 @@
+package parsing
+
+import (
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// ParseError is returned when the token stream does not match the grammar,
+// or when the grammar itself cannot be turned into a parser.
+type ParseError struct {
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the location in the source at which parsing failed.
+func (e *ParseError) Position() shared.Position {
+	return diagnostics.PositionOf(e.Err)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	shared3 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
 
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	shared2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
@@ -17,10 +18,12 @@ type Parser[T shared.TokenTypeConstraint] struct {
 	ruleSet         *Ruleset[T]
 	stateMap        map[shared3.ParsingRuleInterface[T]]fsm.State[ParsingStateArgs[T]]
 	ignoreTokenType T
+	recovery        *RecoveryOptions[T]
 }
 
 // NewParser creates a new parser from the given input
-func NewParser[T shared.TokenTypeConstraint](lexer lexing.LexerInterface[T], parsingRules []shared3.ParsingRuleInterface[T], ignoreTokenType T) *Parser[T] {
+// It returns a ParseError if the parsing FSM cannot be generated from the rules.
+func NewParser[T shared.TokenTypeConstraint](lexer lexing.LexerInterface[T], parsingRules []shared3.ParsingRuleInterface[T], ignoreTokenType T) (*Parser[T], error) {
 	parser := &Parser[T]{
 		lexer:           lexer,
 		ruleSet:         NewRuleset[T](parsingRules),
@@ -29,12 +32,18 @@ func NewParser[T shared.TokenTypeConstraint](lexer lexing.LexerInterface[T], par
 
 	stateMap, err := parser.generateFSM()
 	if err != nil {
-		panic(fmt.Sprintf("failed to generate FSM: %v", err))
+		return nil, &ParseError{Err: fmt.Errorf("failed to generate FSM: %w", err)}
 	}
 
 	parser.stateMap = stateMap
 
-	return parser
+	return parser, nil
+}
+
+// EnableRecovery makes Parse resynchronize after syntax errors instead of stopping at the first one.
+// Parse then returns the partial tree together with a ParseError listing every error.
+func (p *Parser[T]) EnableRecovery(options RecoveryOptions[T]) {
+	p.recovery = &options
 }
 
 func startState[T shared.TokenTypeConstraint](ctx context.Context, args ParsingStateArgs[T]) (ParsingStateArgs[T], fsm.State[ParsingStateArgs[T]], error) {
@@ -44,8 +53,16 @@ func startState[T shared.TokenTypeConstraint](ctx context.Context, args ParsingS
 
 	rule, err := args.parser.ruleSet.GetMatchingRule(args.tokens, args.currentIndex)
 
+	if recovery := args.parser.recovery; recovery != nil && (err != nil || rule.Symbol() == recovery.ErrorSymbol) {
+		node, errs, skipped := args.parser.resynchronize(args.tokens, args.currentIndex)
+		args.currentBuffer.Children = append(args.currentBuffer.Children, node)
+		args.errors = append(args.errors, errs...)
+		args.currentIndex += skipped
+		return args, startState, nil
+	}
+
 	if err != nil {
-		return args, nil, fmt.Errorf("no matching rule found: %w", err)
+		return args, nil, diagnostics.Errorf(args.tokens[args.currentIndex].Position, "no matching rule found: %w", err)
 	}
 
 	fn := args.parser.stateMap[rule]
@@ -53,11 +70,16 @@ func startState[T shared.TokenTypeConstraint](ctx context.Context, args ParsingS
 	return fn(ctx, args)
 }
 
-// Parse parses the input and returns the parse tree
+// Parse parses the input and returns the parse tree.
+// With recovery enabled, a partial tree is returned alongside the error.
 func (p *Parser[T]) Parse() (*shared2.ParseTree[T], error) {
 	// Reset lexer to be sure it works
 	p.lexer.Reset()
 	tokens, err := p.lexer.GetTokens()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tokens: %w", err)
+	}
+
 	newTokens := make([]*shared.Token[T], 0)
 
 	//ogNum := len(tokens)
@@ -76,12 +98,6 @@ func (p *Parser[T]) Parse() (*shared2.ParseTree[T], error) {
 	//fmt.Println("Num of Ignored Tokens: ", ogNum-len(tokens))
 	//fmt.Println("-------------")
 
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tokens: %q", err)
-	}
-
-	fmt.Println("Starting Parsing Process...")
-
 	args := ParsingStateArgs[T]{
 		tokens:       tokens,
 		currentToken: nil,
@@ -94,10 +110,12 @@ func (p *Parser[T]) Parse() (*shared2.ParseTree[T], error) {
 
 	args, err = fsm.Run(context.Background(), args, startState)
 	if err != nil {
-		return nil, fmt.Errorf("parsing failed: %q", err)
+		return nil, &ParseError{Err: fmt.Errorf("parsing failed: %w", err)}
 	}
 
-	fmt.Println("Parsing Process Complete!")
+	if len(args.errors) > 0 {
+		return args.currentBuffer, &ParseError{Err: fmt.Errorf("parsing failed: %w", diagnostics.List(args.errors))}
+	}
 
 	return args.currentBuffer, nil
 }
@@ -109,6 +127,7 @@ type ParsingStateArgs[T shared.TokenTypeConstraint] struct {
 	currentToken  *shared.Token[T]
 	currentIndex  int
 	currentBuffer *shared2.ParseTree[T]
+	errors        []*diagnostics.Diagnostic
 }
 
 // generateFSM generates the FSM for parsing
@@ -125,7 +144,7 @@ func (p *Parser[T]) generateFSM() (map[shared3.ParsingRuleInterface[T]]fsm.State
 
 			node, err, consumed := rule.Match(args.tokens, args.currentIndex)
 			if err != nil {
-				return args, nil, fmt.Errorf("rule %s failed to match: %w", rule.Symbol(), err)
+				return args, nil, diagnostics.Errorf(args.currentToken.Position, "rule %s failed to match: %w", rule.Symbol(), err)
 			}
 			if node == nil {
 				args.currentIndex += consumed
@@ -0,0 +1,147 @@
+package parsing
+
+import (
+	"slices"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	shared3 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
+	shared2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+)
+
+// RecoveryOptions tells the parser how to resynchronize after a syntax error,
+// so that a single run reports every error instead of only the first.
+type RecoveryOptions[T shared.TokenTypeConstraint] struct {
+	// ErrorSymbol is the symbol of the fallback rule that swallows unrecognized tokens.
+	// Broken constructs are replaced by a node with this symbol in the partial tree.
+	ErrorSymbol string
+
+	// BlockOpen and BlockClose delimit blocks. Closing the broken block ends the skipped region.
+	BlockOpen  T
+	BlockClose T
+
+	// ResumeTokens start a new top-level construct. Skipping always stops before one.
+	ResumeTokens []T
+
+	// NestedRules are re-parsed when their first token is reached while skipping,
+	// so that errors in later blocks of a broken construct are reported as well.
+	NestedRules map[T]shared3.ParsingRuleInterface[T]
+
+	// ItemScope is the keyword of blocks whose items are re-parsed one by one while skipping, e.g. the rules of a RULES block.
+	// ItemRules maps the first token of an item to its rule, so that every broken item in the block is reported.
+	ItemScope T
+	ItemRules map[T]shared3.ParsingRuleInterface[T]
+}
+
+// resynchronize records the syntax error at index and skips to the next synchronization point.
+// It returns the node that replaces the broken construct, the errors found and the number of tokens skipped.
+func (p *Parser[T]) resynchronize(tokens []*shared.Token[T], index int) (*shared2.ParseTree[T], []*diagnostics.Diagnostic, int) {
+	options := p.recovery
+	failure := p.explain(tokens, index)
+	errs := []*diagnostics.Diagnostic{syntaxError(tokens, index, failure)}
+
+	errorIndex := failure.Index
+	resumeFrom := max(errorIndex, index+1)
+	depth := 0
+	current := index
+	// itemDepth is the depth of the item scope block being skipped through, or 0 outside of one.
+	itemDepth := 0
+	scopeOpening := false
+
+	for current < len(tokens) {
+		tokenType := tokens[current].Type
+
+		if current >= resumeFrom {
+			if slices.Contains(options.ResumeTokens, tokenType) {
+				break
+			}
+
+			rule, ok := options.NestedRules[tokenType]
+			if !ok && itemDepth > 0 {
+				rule, ok = options.ItemRules[tokenType]
+			}
+			if ok {
+				consumed, nested := reparse(rule, tokens, current)
+				if nested == nil {
+					current += consumed
+					continue
+				}
+
+				errs = append(errs, syntaxError(tokens, current, nested))
+				errorIndex = nested.Index
+				resumeFrom = max(errorIndex, current+1)
+			}
+		}
+
+		switch tokenType {
+		case options.ItemScope:
+			scopeOpening = options.ItemRules != nil
+		case options.BlockOpen:
+			depth++
+			if scopeOpening {
+				itemDepth = depth
+				scopeOpening = false
+			}
+		case options.BlockClose:
+			if depth == itemDepth {
+				itemDepth = 0
+			}
+			depth--
+		}
+		current++
+
+		if tokenType == options.BlockClose && depth <= 0 && current > errorIndex {
+			break
+		}
+	}
+
+	node := &shared2.ParseTree[T]{
+		Symbol: options.ErrorSymbol,
+		Token:  tokens[index],
+	}
+	return node, errs, current - index
+}
+
+// reparse matches the rule at index and returns the number of tokens it consumed, or why it does not match.
+func reparse[T shared.TokenTypeConstraint](rule shared3.ParsingRuleInterface[T], tokens []*shared.Token[T], index int) (int, *shared3.MatchError) {
+	if _, err, consumed := rule.Match(tokens, index); err == nil {
+		return consumed, nil
+	}
+
+	failure := shared3.Explain(rule, tokens, index)
+	if failure == nil {
+		failure = shared3.NewMatchError(tokens, index, "unexpected %s", shared3.DescribeToken(tokens, index))
+	}
+	return 0, failure
+}
+
+// explain returns the furthest failure of all regular top-level rules at index.
+func (p *Parser[T]) explain(tokens []*shared.Token[T], index int) *shared3.MatchError {
+	var furthest *shared3.MatchError
+	for _, rule := range p.ruleSet.Rules {
+		if rule.Symbol() == p.recovery.ErrorSymbol {
+			continue
+		}
+		furthest = shared3.Furthest(furthest, shared3.Explain(rule, tokens, index))
+	}
+
+	if furthest == nil {
+		return shared3.NewMatchError(tokens, index, "unexpected %s", shared3.DescribeToken(tokens, index))
+	}
+	return furthest
+}
+
+// syntaxError turns a match failure into a positioned diagnostic.
+func syntaxError[T shared.TokenTypeConstraint](tokens []*shared.Token[T], index int, failure *shared3.MatchError) *diagnostics.Diagnostic {
+	if failure.Index == index {
+		return &diagnostics.Diagnostic{
+			Position: failure.Position,
+			Err:      shared3.NewMatchError(tokens, index, "unexpected %s", shared3.DescribeToken(tokens, index)),
+		}
+	}
+
+	return &diagnostics.Diagnostic{
+		Position: failure.Position,
+		Err:      failure,
+	}
+}
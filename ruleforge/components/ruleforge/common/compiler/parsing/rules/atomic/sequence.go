@@ -1,7 +1,6 @@
 package atomic
 
 import (
-	"fmt"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -28,15 +27,15 @@ type SequenceRule[T lexshared.TokenTypeConstraint] struct {
 }
 
 func (r *SequenceRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
-	if index+len(r.sequence) > len(tokens) {
-		return nil, fmt.Errorf("not enough tokens for sequence %s", r.Symbol()), 0
-	}
-
 	children := make([]*parseshared.ParseTree[T], len(r.sequence))
 	for i, expectedType := range r.sequence {
+		if index+i >= len(tokens) {
+			return nil, shared.NewMatchError(tokens, index+i, "expected %v in %s, found end of input", expectedType, r.Symbol()), 0
+		}
+
 		token := tokens[index+i]
 		if token.Type != expectedType {
-			return nil, fmt.Errorf("token mismatch in sequence %s at pos %d: expected %v, got %v", r.Symbol(), i, expectedType, token.Type), 0
+			return nil, shared.NewMatchError(tokens, index+i, "expected %v in %s, found %s", expectedType, r.Symbol(), shared.DescribeToken(tokens, index+i)), 0
 		}
 		children[i] = &parseshared.ParseTree[T]{
 			Symbol: r.childSymbols[i],
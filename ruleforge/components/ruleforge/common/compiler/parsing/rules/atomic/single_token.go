@@ -1,7 +1,6 @@
 package atomic
 
 import (
-	"fmt"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -24,7 +23,7 @@ type SingleTokenRule[T lexshared.TokenTypeConstraint] struct {
 
 func (r *SingleTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
 	if index >= len(tokens) {
-		return nil, fmt.Errorf("not enough tokens for %s, expected %v", r.Symbol(), r.tokenType), 0
+		return nil, shared.NewMatchError(tokens, index, "expected %v, found %s", r.tokenType, shared.DescribeToken(tokens, index)), 0
 	}
 
 	if token := tokens[index]; token.Type == r.tokenType {
@@ -35,5 +34,5 @@ func (r *SingleTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*pa
 		return tree, nil, 1
 	}
 
-	return nil, fmt.Errorf("token mismatch for %s: expected %v, got %v", r.Symbol(), r.tokenType, tokens[index].Type), 0
+	return nil, shared.NewMatchError(tokens, index, "expected %v, found %s", r.tokenType, shared.DescribeToken(tokens, index)), 0
 }
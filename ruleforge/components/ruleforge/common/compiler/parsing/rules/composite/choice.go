@@ -1,7 +1,7 @@
 package composite
 
 import (
-	"fmt"
+	"errors"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -23,12 +23,37 @@ type ChoiceRule[T lexshared.TokenTypeConstraint] struct {
 }
 
 func (r *ChoiceRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
+	var furthest *shared.MatchError
 	for _, subrule := range r.subrules {
 		nodes, err, consumed := subrule.Match(tokens, index)
 		if err == nil {
 			return nodes, err, consumed
 		}
+
+		var matchErr *shared.MatchError
+		if errors.As(err, &matchErr) {
+			furthest = shared.Furthest(furthest, matchErr)
+		}
+	}
+
+	if furthest == nil {
+		return nil, shared.NewMatchError(tokens, index, "no matched subrule for %s", r.Symbol()), 0
+	}
+	return nil, furthest, 0
+}
+
+// Explain returns the explanation of the matching subrule, or the furthest failure of all subrules.
+func (r *ChoiceRule[T]) Explain(tokens []*lexshared.Token[T], index int) *shared.MatchError {
+	var furthest *shared.MatchError
+	for _, subrule := range r.subrules {
+		if _, err, _ := subrule.Match(tokens, index); err == nil {
+			return shared.Explain(subrule, tokens, index)
+		}
+		furthest = shared.Furthest(furthest, shared.Explain(subrule, tokens, index))
 	}
 
-	return nil, fmt.Errorf("no matched subrule"), 0
+	if furthest == nil {
+		return shared.NewMatchError(tokens, index, "no matched subrule for %s", r.Symbol())
+	}
+	return furthest
 }
@@ -0,0 +1,43 @@
+package composite
+
+import (
+	"sync"
+
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
+	parseshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+)
+
+// NewLazyRule creates a rule that builds its actual rule on first use.
+// It allows recursive grammars, e.g. parenthesized groups that contain further groups,
+// whose rules would otherwise have to be constructed infinitely deep.
+func NewLazyRule[T lexshared.TokenTypeConstraint](symbol string, build func() shared.ParsingRuleInterface[T]) shared.ParsingRuleInterface[T] {
+	return &LazyRule[T]{
+		BaseParsingRule: internal.BaseParsingRule[T]{SymbolString: symbol},
+		build:           build,
+	}
+}
+
+// LazyRule defers the construction of its rule until it is first matched.
+type LazyRule[T lexshared.TokenTypeConstraint] struct {
+	internal.BaseParsingRule[T]
+	build func() shared.ParsingRuleInterface[T]
+	once  sync.Once
+	rule  shared.ParsingRuleInterface[T]
+}
+
+func (r *LazyRule[T]) resolve() shared.ParsingRuleInterface[T] {
+	r.once.Do(func() { r.rule = r.build() })
+	return r.rule
+}
+
+// Match matches the built rule; the resulting tree keeps the built rule's symbol.
+func (r *LazyRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
+	return r.resolve().Match(tokens, index)
+}
+
+// Explain explains the built rule.
+func (r *LazyRule[T]) Explain(tokens []*lexshared.Token[T], index int) *shared.MatchError {
+	return shared.Explain(r.resolve(), tokens, index)
+}
@@ -43,3 +43,22 @@ func (r *NestedRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parsesh
 	}
 	return tree, nil, totalConsumed
 }
+
+// Explain returns the furthest failure among the child that failed and the children before it.
+// Earlier children matter because a repetition that stopped on a broken item still succeeds.
+func (r *NestedRule[T]) Explain(tokens []*lexshared.Token[T], index int) *shared.MatchError {
+	var furthest *shared.MatchError
+	currentIndex := index
+
+	for _, rule := range r.childRules {
+		furthest = shared.Furthest(furthest, shared.Explain(rule, tokens, currentIndex))
+
+		_, err, consumed := rule.Match(tokens, currentIndex)
+		if err != nil {
+			return furthest
+		}
+		currentIndex += consumed
+	}
+
+	return nil
+}
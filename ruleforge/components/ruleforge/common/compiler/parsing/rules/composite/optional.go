@@ -46,3 +46,11 @@ func (r *OptionalRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parse
 	}
 	return tree, nil, consumed
 }
+
+// Explain returns the child's failure only if the child was partially matched.
+func (r *OptionalRule[T]) Explain(tokens []*lexshared.Token[T], index int) *shared.MatchError {
+	if failure := shared.Explain(r.childRule, tokens, index); failure != nil && failure.Index > index {
+		return failure
+	}
+	return nil
+}
@@ -54,3 +54,19 @@ func (r *PairRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshar
 	}
 	return tree, nil, firstConsumed + secondConsumed
 }
+
+// Explain returns the furthest failure of the first element and, if it matched, the second element.
+func (r *PairRule[T]) Explain(tokens []*lexshared.Token[T], index int) *shared.MatchError {
+	failure := shared.Explain(r.firstRule, tokens, index)
+
+	_, err, firstConsumed := r.firstRule.Match(tokens, index)
+	if err != nil {
+		return failure
+	}
+
+	second := shared.Explain(r.secondRule, tokens, index+firstConsumed)
+	if second == nil {
+		return nil
+	}
+	return shared.Furthest(failure, second)
+}
@@ -52,3 +52,18 @@ func (r *RepetitionRule[T]) Match(tokens []*lexshared.Token[T], index int) (*par
 	}
 	return tree, nil, totalConsumed
 }
+
+// Explain returns the furthest failure of an item that was partially matched where the repetition stopped.
+// Items that fail on their very first token are the normal end of the repetition and are not reported.
+func (r *RepetitionRule[T]) Explain(tokens []*lexshared.Token[T], index int) *shared.MatchError {
+	_, _, consumed := r.Match(tokens, index)
+	stopIndex := index + consumed
+
+	var furthest *shared.MatchError
+	for _, rule := range r.childRules {
+		if failure := shared.Explain(rule, tokens, stopIndex); failure != nil && failure.Index > stopIndex {
+			furthest = shared.Furthest(furthest, failure)
+		}
+	}
+	return furthest
+}
@@ -1,7 +1,6 @@
 package conditional
 
 import (
-	"fmt"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -24,7 +23,7 @@ type AnyTokenRule[T lexshared.TokenTypeConstraint] struct {
 func (r *AnyTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
 	// This rule always succeeds as long as there is a token to consume.
 	if index >= len(tokens) {
-		return nil, fmt.Errorf("no tokens left to match for %s", r.Symbol()), 0
+		return nil, shared.NewMatchError(tokens, index, "no tokens left to match for %s", r.Symbol()), 0
 	}
 
 	tree := &parseshared.ParseTree[T]{
@@ -1,7 +1,6 @@
 package conditional
 
 import (
-	"fmt"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -24,7 +23,9 @@ type ChoiceTokenRule[T lexshared.TokenTypeConstraint] struct {
 }
 
 func (r *ChoiceTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
-	listFormatted := extensions.GetFormattedString(r.tokenTypes)
+	if index >= len(tokens) {
+		return nil, shared.NewMatchError(tokens, index, "expected one of %v, found end of input", extensions.GetFormattedString(r.tokenTypes)), 0
+	}
 
 	if token := tokens[index]; extensions.Contains(r.tokenTypes, token.Type) {
 		tree := &parseshared.ParseTree[T]{
@@ -34,5 +35,5 @@ func (r *ChoiceTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*pa
 		return tree, nil, 1
 	}
 
-	return nil, fmt.Errorf("token mismatch for %s: expected %v, got %v", r.Symbol(), listFormatted, tokens[index].Type), 0
+	return nil, shared.NewMatchError(tokens, index, "expected one of %v, found %s", extensions.GetFormattedString(r.tokenTypes), shared.DescribeToken(tokens, index)), 0
 }
@@ -1,7 +1,6 @@
 package conditional
 
 import (
-	"fmt"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -24,7 +23,7 @@ type ExceptTokenRule[T lexshared.TokenTypeConstraint] struct {
 
 func (r *ExceptTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*parseshared.ParseTree[T], error, int) {
 	if index >= len(tokens) {
-		return nil, fmt.Errorf("not enough tokens for %s", r.SymbolString), 0
+		return nil, shared.NewMatchError(tokens, index, "expected %s, found end of input", r.SymbolString), 0
 	}
 
 	if token := tokens[index]; token.Type != r.excludedType {
@@ -35,5 +34,5 @@ func (r *ExceptTokenRule[T]) Match(tokens []*lexshared.Token[T], index int) (*pa
 		return tree, nil, 1 // Consumes 1 token
 	}
 
-	return nil, fmt.Errorf("token matched excluded type %v for rule %s", r.excludedType, r.Symbol()), 0
+	return nil, shared.NewMatchError(tokens, index, "unexpected %s in %s", shared.DescribeToken(tokens, index), r.Symbol()), 0
 }
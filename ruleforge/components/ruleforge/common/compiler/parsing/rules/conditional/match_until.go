@@ -1,7 +1,6 @@
 package conditional
 
 import (
-	"fmt"
 	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/internal"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/shared"
@@ -42,7 +41,7 @@ func (r *MatchUntilRule[T]) Match(tokens []*lexshared.Token[T], index int) (*par
 	}
 
 	if len(children) == 0 {
-		return nil, fmt.Errorf("rule %s expected at least one token before terminator %v", r.SymbolString, r.terminator), 0
+		return nil, shared.NewMatchError(tokens, index, "rule %s expected at least one token before terminator %v", r.SymbolString, r.terminator), 0
 	}
 
 	tree := &parseshared.ParseTree[T]{
@@ -95,7 +94,7 @@ func (r *TokenSetRepetitionRule[T]) Match(tokens []*lexshared.Token[T], index in
 	}
 
 	if len(children) == 0 {
-		return nil, fmt.Errorf("rule %s expected at least one token from the allowed set", r.Symbol()), 0
+		return nil, shared.NewMatchError(tokens, index, "rule %s expected at least one token from the allowed set, found %s", r.Symbol(), shared.DescribeToken(tokens, index)), 0
 	}
 
 	tree := &parseshared.ParseTree[T]{
@@ -0,0 +1,85 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// MatchError reports the token at which a rule stopped matching.
+type MatchError struct {
+	// Index is the index of the offending token, or len(tokens) at the end of the input.
+	Index    int
+	Position shared.Position
+	Err      error
+}
+
+// NewMatchError creates a MatchError for the token at index. It supports %w like fmt.Errorf.
+func NewMatchError[T shared.TokenTypeConstraint](tokens []*shared.Token[T], index int, format string, args ...any) *MatchError {
+	var position shared.Position
+	if index < len(tokens) {
+		position = tokens[index].Position
+	} else if len(tokens) > 0 {
+		position = tokens[len(tokens)-1].Position
+	}
+
+	return &MatchError{
+		Index:    index,
+		Position: position,
+		Err:      fmt.Errorf(format, args...),
+	}
+}
+
+func (e *MatchError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *MatchError) Unwrap() error {
+	return e.Err
+}
+
+// Explainer is implemented by rules that are built from other rules.
+// Explain returns the furthest failure that made the rule fail or stop consuming early, or nil if there is none.
+// It is only used after a failed parse, so it may freely re-run matches.
+type Explainer[T shared.TokenTypeConstraint] interface {
+	Explain(tokens []*shared.Token[T], index int) *MatchError
+}
+
+// Explain asks the rule why it fails at index.
+// Rules that do not implement Explainer are explained by their own Match error.
+func Explain[T shared.TokenTypeConstraint](rule ParsingRuleInterface[T], tokens []*shared.Token[T], index int) *MatchError {
+	if explainer, ok := rule.(Explainer[T]); ok {
+		return explainer.Explain(tokens, index)
+	}
+
+	_, err, _ := rule.Match(tokens, index)
+	if err == nil {
+		return nil
+	}
+
+	var matchErr *MatchError
+	if errors.As(err, &matchErr) {
+		return matchErr
+	}
+	return NewMatchError(tokens, index, "%s: %w", rule.Symbol(), err)
+}
+
+// Furthest returns whichever failure got further into the input, preferring a on ties.
+func Furthest(a, b *MatchError) *MatchError {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Index >= b.Index {
+		return a
+	}
+	return b
+}
+
+// DescribeToken names the token at index for use in error messages.
+func DescribeToken[T shared.TokenTypeConstraint](tokens []*shared.Token[T], index int) string {
+	if index >= len(tokens) {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q (%v)", tokens[index].ValueToString(), tokens[index].Type)
+}
@@ -43,7 +43,7 @@ func (pt *ParseTree[T]) Print(indent int, ignoreTokens []T) {
 
 func (pt *ParseTree[T]) GetNumberOfTokens() int {
 	if pt == nil {
-		panic("ParseTree[T] is nil")
+		return 0
 	}
 
 	count := 0
@@ -59,6 +59,26 @@ func (pt *ParseTree[T]) GetNumberOfTokens() int {
 	return count
 }
 
+// Position returns the source position of the first token in the tree.
+// It returns the zero Position if the tree holds no tokens.
+func (pt *ParseTree[T]) Position() shared.Position {
+	if pt == nil {
+		return shared.Position{}
+	}
+
+	if pt.Token != nil {
+		return pt.Token.Position
+	}
+
+	for _, child := range pt.Children {
+		if position := child.Position(); position.IsValid() {
+			return position
+		}
+	}
+
+	return shared.Position{}
+}
+
 // GetNthGenDescendantSymbols returns the symbols of all descendants at the given generation depth n.
 // Generation 1 are the immediate children, generation 2 are grandchildren, and so on.
 func (pt *ParseTree[T]) GetNthGenDescendantSymbols(n int) []string {
@@ -94,17 +114,16 @@ func (pt *ParseTree[T]) GetNthGenDescendantTokens(n int) []*shared.Token[T] {
 }
 
 // FindSymbolNode searches the parse tree for the first node whose Symbol equals searchSymbol.
-// It panics if no such node is found in the tree.
+// It returns nil if no such node is found in the tree (or if the tree itself is nil).
 func (pt *ParseTree[T]) FindSymbolNode(searchSymbol string) *ParseTree[T] {
-	if node := pt.findSymbolNode(searchSymbol); node != nil {
-		return node
-	}
-
-	panic(fmt.Sprintf("symbol %q not found in parse tree", searchSymbol))
+	return pt.findSymbolNode(searchSymbol)
 }
 
 // findSymbolNode is a helper that returns the node matching searchSymbol, or nil if not found.
 func (pt *ParseTree[T]) findSymbolNode(searchSymbol string) *ParseTree[T] {
+	if pt == nil {
+		return nil
+	}
 	if pt.Symbol == searchSymbol {
 		return pt
 	}
@@ -126,6 +145,9 @@ func (pt *ParseTree[T]) FindAllSymbolNodes(searchSymbol string) []*ParseTree[T]
 
 // collectSymbolNodes is a helper that appends matching nodes to the provided slice.
 func (pt *ParseTree[T]) collectSymbolNodes(searchSymbol string, matches *[]*ParseTree[T]) {
+	if pt == nil {
+		return
+	}
 	if pt.Symbol == searchSymbol {
 		*matches = append(*matches, pt)
 	}
@@ -153,6 +175,9 @@ func (pt *ParseTree[T]) collectSymbolAndTokenTypes(
 	tokenTypes []T,
 	matches *[]*ParseTree[T],
 ) {
+	if pt == nil {
+		return
+	}
 	if pt.Symbol == searchSymbol && (pt.Token != nil && slices.Contains(tokenTypes, pt.Token.Type)) {
 		*matches = append(*matches, pt)
 	}
@@ -2,7 +2,9 @@ package compilation
 
 import (
 	"fmt"
+	"log"
 
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
@@ -14,9 +16,10 @@ import (
 
 // Compiler is now a lean orchestrator without prewired build.
 type Compiler struct {
-	treeWalker   *TreeWalker
-	styleManager *StyleManager
-	ruleFactory  *RuleFactory
+	parseTree   *shared.ParseTree[symbols.LexingTokenType]
+	styles      map[string]config.Style
+	defines     map[string]string
+	ruleFactory *RuleFactory
 
 	validBaseTypes        []string
 	armorBases            []model.ItemBase
@@ -30,6 +33,9 @@ type Compiler struct {
 	baseTypeData          []config.BaseTypeAutomationEntry
 	cssVariables          map[string]string
 	customPresets         map[string]config.EquipmentPreset
+	log                   *log.Logger
+	// warnings is shared by all strictness levels, so that each warning is reported once per script.
+	warnings *model2.Warnings
 }
 
 // NewCompiler constructs a Compiler and captures all dependencies except the Build.
@@ -47,17 +53,23 @@ func NewCompiler(
 	cssVariables map[string]string,
 	customPresets map[string]config.EquipmentPreset,
 ) (*Compiler, error) {
-	styleMgr, err := NewStyleManager(configuration.StyleJsonPath, parseTree, cssVariables)
-	if err != nil {
-		return nil, err
+	styles := configuration.Styles
+	if styles == nil {
+		var err error
+		styles, err = config.LoadStyles(configuration.StyleJsonPath, cssVariables)
+		if err != nil {
+			return nil, &CompileError{Err: fmt.Errorf("failed to load styles: %w", err)}
+		}
 	}
 
-	armorBases, weaponBases, flaskBases := prepareItemData(itemBases, validBaseTypes)
+	logger := configuration.logger()
+	armorBases, weaponBases, flaskBases := prepareItemData(itemBases, validBaseTypes, logger)
 
 	return &Compiler{
-		treeWalker:   NewTreeWalker(parseTree),
-		styleManager: styleMgr,
-		ruleFactory:  &RuleFactory{},
+		parseTree:   parseTree,
+		styles:      styles,
+		defines:     configuration.Defines,
+		ruleFactory: &RuleFactory{},
 
 		validBaseTypes:        validBaseTypes,
 		armorBases:            armorBases,
@@ -71,19 +83,50 @@ func NewCompiler(
 		baseTypeData:          baseTypeData,
 		cssVariables:          cssVariables,
 		customPresets:         customPresets,
+		log:                   logger,
+		warnings:              model2.NewWarnings(logger),
 	}, nil
 }
 
+// CompiledFilter is the output of a single strictness level of a script.
+type CompiledFilter struct {
+	Strictness model2.Strictness
+	Lines      []string
+	// Blocks records where each Show/Hide block of Lines came from, in order.
+	Blocks []CompiledBlock
+}
+
+// CompiledBlock locates a block in the filter and the script construct it was generated from.
+type CompiledBlock struct {
+	// Line is the 1-based line of the block's Show/Hide keyword in the filter.
+	Line   int
+	Origin BlockOrigin
+}
+
+// BlockOrigin tells which part of a script produced a filter block.
+type BlockOrigin struct {
+	Section string
+	// Source is "rule", "fallback" or the macro, e.g. `macro "unique_tiering"`.
+	Source string
+	// Style is the style reference of a rule as written in the script; it is empty for macros and the fallback.
+	Style string
+	// Position is the position of the rule or macro in the script. The fallback has no position.
+	Position lexshared.Position
+}
+
+// FileName returns the output file name for this filter, e.g. "MyFilter-3-STRICT.filter".
+func (f CompiledFilter) FileName(name string) string {
+	return fmt.Sprintf("%s-%d-%s.filter", name, int(f.Strictness), f.Strictness)
+}
+
 // CompileIntoFilter orchestrates compilation, wiring the correct Build based on metadata.
+// It produces one filter per strictness level, from ALL to SUPER-STRICT.
+// IF blocks are resolved for each level, as their conditions can depend on the strictness.
 //
 //goland:noinspection t
-func (c *Compiler) CompileIntoFilter() ([]string, error, string) {
-	var header, body, toc []string
-
+func (c *Compiler) CompileIntoFilter() ([]CompiledFilter, error, string) {
 	// 1. Extract raw data
-	metadata := c.treeWalker.ExtractMetadata()
-	variables := c.treeWalker.ExtractVariables()
-	sections := c.treeWalker.ExtractSections()
+	metadata := NewTreeWalker(c.parseTree).ExtractMetadata()
 
 	// 1b. Determine Build: default first, then custom preset
 	buildName := metadata.Build
@@ -93,15 +136,52 @@ func (c *Compiler) CompileIntoFilter() ([]string, error, string) {
 	} else if cp, ok := c.customPresets[buildName]; ok {
 		ep, err := NewEquipmentPresetFromConfig(cp)
 		if err != nil {
-			return nil, err, metadata.Name
+			return nil, &CompileError{Err: err}, metadata.Name
 		}
 		buildInstance = &Build{Name: buildName, Preset: ep}
 	} else {
-		return nil, fmt.Errorf("build preset %q not found", buildName), metadata.Name
+		return nil, &CompileError{Err: fmt.Errorf("build preset %q not found", buildName)}, metadata.Name
 	}
 
+	levels := model2.AllStrictnessLevels()
+	filters := make([]CompiledFilter, 0, len(levels))
+	for _, level := range levels {
+		resolvedTree, err := ResolveConditionals(c.parseTree, ConditionalContext{Build: metadata.Build, Strictness: level, Defines: c.defines, Warnings: c.warnings})
+		if err != nil {
+			return nil, &CompileError{Err: fmt.Errorf("strictness %s: %w", level, err)}, metadata.Name
+		}
+
+		lines, blocks, err := c.compileForStrictness(level, metadata, resolvedTree, buildInstance)
+		if err != nil {
+			return nil, &CompileError{Err: fmt.Errorf("strictness %s: %w", level, err)}, metadata.Name
+		}
+		filters = append(filters, CompiledFilter{Strictness: level, Lines: lines, Blocks: blocks})
+	}
+
+	return filters, nil, metadata.Name
+}
+
+// compileForStrictness compiles the script, with its IF blocks resolved, into a single filter for the given strictness level.
+func (c *Compiler) compileForStrictness(
+	strictness model2.Strictness,
+	metadata ExtractedMetadata,
+	resolvedTree *shared.ParseTree[symbols.LexingTokenType],
+	buildInstance *Build,
+) ([]string, []CompiledBlock, error) {
+	treeWalker := NewTreeWalker(resolvedTree)
+	variables, err := treeWalker.ExtractVariables()
+	if err != nil {
+		return nil, nil, err
+	}
+	sections := treeWalker.ExtractSections()
+	styleManager := NewStyleManagerFromStyles(c.styles, resolvedTree)
+
+	var header, body, toc []string
+	// Block lines are indices into body until the final output is assembled.
+	var blocks []CompiledBlock
+
 	// 2. Construct the header
-	header = c.constructHeader(metadata)
+	header = c.constructHeader(metadata, strictness)
 
 	// 3. Pre-calculate sizes to determine the starting line number
 	tocSize := 1 + len(sections) + 1
@@ -114,7 +194,7 @@ func (c *Compiler) CompileIntoFilter() ([]string, error, string) {
 	// 4. Instantiate a RuleGenerator with the resolved build
 	ruleGenerator := NewRuleGenerator(
 		c.ruleFactory,
-		c.styleManager,
+		styleManager,
 		c.validBaseTypes,
 		c.armorBases,
 		c.weaponBases,
@@ -126,6 +206,9 @@ func (c *Compiler) CompileIntoFilter() ([]string, error, string) {
 		c.chasePotentialWeight,
 		c.baseTypeData,
 		buildInstance,
+		strictness,
+		c.log,
+		c.warnings,
 	)
 
 	// 5. Generate rules for each section and track final line numbers
@@ -137,16 +220,20 @@ func (c *Compiler) CompileIntoFilter() ([]string, error, string) {
 
 		compiledRules, err := ruleGenerator.GenerateRulesForSection(section, variables)
 		if err != nil {
-			return nil, err, metadata.Name
+			return nil, nil, err
 		}
 
 		for _, rule := range compiledRules {
-			body = append(body, rule...)
-			lineCounter += len(rule)
+			blocks = append(blocks, CompiledBlock{Line: len(body), Origin: rule.Origin})
+			body = append(body, rule.Lines...)
+			lineCounter += len(rule.Lines)
 		}
 
-		body = body[:len(body)-1]
-		lineCounter--
+		// Rules end with an empty line, which the divider already provides.
+		if len(compiledRules) > 0 {
+			body = body[:len(body)-1]
+			lineCounter--
+		}
 
 		divider := c.constructDivider()
 		body = append(body, divider...)
@@ -167,29 +254,34 @@ func (c *Compiler) CompileIntoFilter() ([]string, error, string) {
 	toc = append(toc, c.constructComment(fmt.Sprintf("\tLine %d: %s (%s)", fallbackLineNumber, fallbackName, fallbackDesc)))
 
 	// 8. Add fallback rules
-	fallbackStyle, _ := c.styleManager.GetStyle("Fallback")
+	fallbackStyle, _ := styleManager.GetStyle("Fallback")
 	fallbackRule := c.ruleFactory.ConstructRule(model2.ShowRule, *fallbackStyle, []string{})
 	fallbackHeading := c.constructSectionHeading(fallbackName, fallbackDesc)
 
 	body = append(body, fallbackHeading, "")
-	body = append(body, fallbackRule...)
+	blocks = append(blocks, CompiledBlock{Line: len(body), Origin: BlockOrigin{Section: fallbackName, Source: "fallback", Style: "Fallback"}})
+	body = append(body, fallbackRule.Lines...)
 
 	// 9. Assemble and return
 	var finalOutput []string
 	finalOutput = append(finalOutput, header...)
 	finalOutput = append(finalOutput, toc...)
 	finalOutput = append(finalOutput, c.constructDivider()...)
+
+	for i := range blocks {
+		blocks[i].Line += len(finalOutput) + 1
+	}
 	finalOutput = append(finalOutput, body...)
 
-	return finalOutput, nil, metadata.Name
+	return finalOutput, blocks, nil
 }
 
-func (c *Compiler) constructHeader(metadata ExtractedMetadata) []string {
+func (c *Compiler) constructHeader(metadata ExtractedMetadata, strictness model2.Strictness) []string {
 	output := make([]string, 0)
 	lines := []string{
 		"This filter is automatically generated through the Ruleforge program.",
 		"Ruleforge metadata (from the user's script): ",
-		fmt.Sprintf("Ruleforge \"%s\" @ %s (meant for: %s) -> strictness: %s", metadata.Name, metadata.Version, metadata.Build, metadata.Strictness),
+		fmt.Sprintf("Ruleforge \"%s\" @ %s (meant for: %s) -> strictness: %s", metadata.Name, metadata.Version, metadata.Build, strictness),
 		"",
 		"For questions reach out to Mr. Hoorn (Ruleforge author):",
 		"Discord: \"mr.hoornasp.learningexpert\" (without quotations)",
@@ -216,7 +308,7 @@ func (c *Compiler) constructDivider() []string {
 }
 
 // prepareItemData filters and categorizes a raw list of item bases.
-func prepareItemData(itemBases []model.ItemBase, validBaseTypes []string) ([]model.ItemBase, []model.ItemBase, []model.ItemBase) {
+func prepareItemData(itemBases []model.ItemBase, validBaseTypes []string, logger *log.Logger) ([]model.ItemBase, []model.ItemBase, []model.ItemBase) {
 	var armorBases, weaponBases, flaskBases []model.ItemBase
 	utils := NewPobUtils()
 
@@ -224,6 +316,9 @@ func prepareItemData(itemBases []model.ItemBase, validBaseTypes []string) ([]mod
 		if !slices.Contains(validBaseTypes, item.GetBaseType()) {
 			continue
 		}
+		if item.DropLevel == nil && (utils.IsArmor(item) || utils.IsWeapon(item) || utils.IsFlask(item)) {
+			logger.Printf("WARNING: Item '%s' has a nil DropLevel; defaulting to level 0.", item.Name)
+		}
 		if utils.IsArmor(item) {
 			armorBases = append(armorBases, item)
 		} else if utils.IsWeapon(item) {
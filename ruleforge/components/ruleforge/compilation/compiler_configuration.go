@@ -1,5 +1,29 @@
 package compilation
 
+import (
+	"log"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
+)
+
 type CompilerConfiguration struct {
 	StyleJsonPath string
+
+	// Styles are the already loaded styles. When set, StyleJsonPath is not read again,
+	// which lets several compilers share one set of styles. The map must not be modified while compilers use it.
+	Styles map[string]config.Style
+
+	// Defines are the values of `$name` in the conditions of IF blocks, e.g. from `-D name=value` on the command line.
+	Defines map[string]string
+
+	// Logger receives the warnings of this compilation. It defaults to the standard logger.
+	Logger *log.Logger
+}
+
+// logger returns the configured logger or the standard logger.
+func (c CompilerConfiguration) logger() *log.Logger {
+	if c.Logger != nil {
+		return c.Logger
+	}
+	return log.Default()
 }
@@ -0,0 +1,149 @@
+package compilation
+
+import (
+	"slices"
+
+	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/conditions"
+)
+
+// combineAlternatives expands the section's and the rule's conditions into the alternatives that each become a block.
+// Within an alternative, a rule condition overrides the section condition with the same identifier and operator.
+// Section conditions with alternatives are not overridden: dropping the overridden condition from one alternative
+// would leave it more general than the others, which would then be removed as covered by it.
+func combineAlternatives(sectionConditions, ruleConditions model2.ConditionExpression) [][]model2.Condition {
+	sectionAlternatives := sectionConditions.Disjunction()
+	override := len(sectionAlternatives) == 1
+
+	var alternatives [][]model2.Condition
+	for _, sectionConjunction := range sectionAlternatives {
+		for _, ruleConjunction := range ruleConditions.Disjunction() {
+			alternatives = append(alternatives, overrideConditions(sectionConjunction, ruleConjunction, override))
+		}
+	}
+	return minimizeAlternatives(alternatives)
+}
+
+// overrideConditions joins the conditions of a section and a rule. If override is set, the rule's conditions
+// replace the section's conditions with the same identifier and operator; otherwise both must hold.
+func overrideConditions(sectionConditions, ruleConditions []model2.Condition, override bool) []model2.Condition {
+	overridden := make(map[string]bool, len(ruleConditions))
+	for _, condition := range ruleConditions {
+		overridden[condition.Identifier+":"+condition.Operator] = override
+	}
+
+	combined := make([]model2.Condition, 0, len(sectionConditions)+len(ruleConditions))
+	for _, condition := range sectionConditions {
+		if !overridden[condition.Identifier+":"+condition.Operator] {
+			combined = append(combined, condition)
+		}
+	}
+	for _, condition := range ruleConditions {
+		if !slices.ContainsFunc(combined, func(existing model2.Condition) bool { return sameCondition(existing, condition) }) {
+			combined = append(combined, condition)
+		}
+	}
+	return combined
+}
+
+// minimizeAlternatives removes alternatives that a more general one already covers
+// and merges alternatives that differ in the values of a single multi-value condition.
+func minimizeAlternatives(alternatives [][]model2.Condition) [][]model2.Condition {
+	for changed := true; changed; {
+		changed = false
+		for i := 0; i < len(alternatives) && !changed; i++ {
+			for j := 0; j < len(alternatives) && !changed; j++ {
+				if i == j {
+					continue
+				}
+				if covers(alternatives[i], alternatives[j]) {
+					alternatives = slices.Delete(alternatives, j, j+1)
+					changed = true
+				} else if merged, ok := mergeAlternatives(alternatives[i], alternatives[j]); ok {
+					alternatives[i] = merged
+					alternatives = slices.Delete(alternatives, j, j+1)
+					changed = true
+				}
+			}
+		}
+	}
+	return alternatives
+}
+
+// covers reports whether every condition of general is also part of specific, making specific redundant.
+func covers(general, specific []model2.Condition) bool {
+	for _, condition := range general {
+		if !slices.ContainsFunc(specific, func(other model2.Condition) bool { return sameCondition(condition, other) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeAlternatives joins two alternatives that are equal except for the values of one multi-value condition.
+func mergeAlternatives(a, b []model2.Condition) ([]model2.Condition, bool) {
+	if len(a) != len(b) {
+		return nil, false
+	}
+
+	onlyInA, onlyInB := -1, -1
+	for i, condition := range a {
+		if slices.ContainsFunc(b, func(other model2.Condition) bool { return sameCondition(condition, other) }) {
+			continue
+		}
+		if onlyInA != -1 {
+			return nil, false
+		}
+		onlyInA = i
+	}
+	for i, condition := range b {
+		if slices.ContainsFunc(a, func(other model2.Condition) bool { return sameCondition(condition, other) }) {
+			continue
+		}
+		if onlyInB != -1 {
+			return nil, false
+		}
+		onlyInB = i
+	}
+	if onlyInA == -1 || onlyInB == -1 {
+		return nil, false
+	}
+
+	left, right := a[onlyInA], b[onlyInB]
+	if left.Identifier != right.Identifier || left.Operator != right.Operator || !mergeable(left) || !mergeable(right) {
+		return nil, false
+	}
+
+	merged := slices.Clone(a)
+	values := slices.Clone(left.Value)
+	for _, value := range right.Value {
+		if !slices.Contains(values, value) {
+			values = append(values, value)
+		}
+	}
+	merged[onlyInA].Value = values
+	return merged, true
+}
+
+// mergeable reports whether alternatives of the condition can be written as one condition with several values,
+// which PoE ORs together on a single line.
+// `!=` with several values excludes all of them, which is an AND, so only matching operators qualify.
+// Influences compared with `==` must all be present, so they are not merged either.
+// Counted conditions count matches among their values, so adding values would change their meaning.
+// Values computed by an expression are only known once compiled, so such conditions are kept apart as well.
+func mergeable(condition model2.Condition) bool {
+	registered, ok := conditions.Lookup(condition.Identifier)
+	if !ok || !registered.Kind.AllowsMultipleValues() || condition.Count != "" || condition.Expression != nil {
+		return false
+	}
+	if registered.Kind == conditions.InfluenceEnum && condition.Operator == "==" {
+		return false
+	}
+	return condition.Operator == "==" || condition.Operator == conditions.ContainsOperator || condition.Operator == ""
+}
+
+// sameCondition compares expressions by identity, since different expressions may still evaluate to the same value.
+func sameCondition(a, b model2.Condition) bool {
+	return a.Identifier == b.Identifier && a.Operator == b.Operator && a.Count == b.Count && slices.Equal(a.Value, b.Value) &&
+		a.Expression == b.Expression
+}
@@ -0,0 +1,136 @@
+package compilation
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/postprocessor"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/imports"
+	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+func TestListExpressionAlternativesAreKept(t *testing.T) {
+	blocks := compileBlocks(t, `
+var a => ["Exalted Orb"]
+var b => ["Divine Orb"]
+
+SECTION {
+  METADATA {
+    NAME        => "Orbs"
+    DESCRIPTION => "Orbs"
+  }
+
+  RULES {
+    WHERE @item_type == $a + ["Chaos Orb"] | @item_type == $b + ["Mirror of Kalandra"] => "Test" => $Show
+    WHERE @item_type == "Divine Orb" | @item_type == $a + ["Chaos Orb"] => "Test" => $Show
+    WHERE @item_type == "Divine Orb" | @item_type == "Chaos Orb" => "Test" => $Show
+  }
+}`)
+
+	want := [][]string{
+		{`BaseType == "Exalted Orb" "Chaos Orb"`},
+		{`BaseType == "Divine Orb" "Mirror of Kalandra"`},
+		{`BaseType == "Divine Orb"`},
+		{`BaseType == "Exalted Orb" "Chaos Orb"`},
+		{`BaseType == "Divine Orb" "Chaos Orb"`},
+	}
+	assertBlocks(t, blocks, want)
+}
+
+func TestRuleConditionsDoNotOverrideSectionAlternatives(t *testing.T) {
+	blocks := compileBlocks(t, `
+SECTION {
+  METADATA {
+    NAME        => "Jewellery"
+    DESCRIPTION => "Jewellery"
+  }
+
+  SECTION_CONDITIONS {
+    WHERE @area_level >= 10 | @item_class == "Belts"
+  }
+
+  RULES {
+    WHERE (@rarity == "Rare" | @rarity == "Unique") -> @item_class == "Rings" => "Test" => $Show
+  }
+}`)
+
+	want := [][]string{
+		{`AreaLevel >= "10"`, `Class == "Rings"`, `Rarity == "Rare" "Unique"`},
+		{`Class == "Belts"`, `Class == "Rings"`, `Rarity == "Rare" "Unique"`},
+	}
+	assertBlocks(t, blocks, want)
+}
+
+// compileBlocks compiles the sections of a script at STRICT and returns the conditions of each block, fallback excluded.
+func compileBlocks(t *testing.T, sections string) [][]string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.rf")
+	script := `METADATA {
+  NAME       => "Test"
+  VERSION    => "1.0"
+  STRICTNESS => STRICT
+  BUILD      => "SHADOW"
+}
+` + sections
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tree, _, err := imports.NewResolver().Resolve(path)
+	if err != nil {
+		t.Fatal(diagnostics.Render(err))
+	}
+	pp := postprocessor.PostProcessor[symbols.LexingTokenType]{}
+	tree = pp.FilterOutSymbols([]string{symbols.ParseSymbolWhitespace.String(), symbols.ParseSymbolBlockOperator.String()}, tree)
+	tree = pp.RemoveEmptyNodes(tree)
+
+	styles := map[string]config.Style{"Test": {}, "Fallback": {}}
+	compiler, err := NewCompiler(tree, CompilerConfiguration{Styles: styles, Logger: log.New(io.Discard, "", 0)}, nil, nil, nil, config.EconomyWeights{}, nil, "Global", 0, nil, nil, nil)
+	if err != nil {
+		t.Fatal(diagnostics.Render(err))
+	}
+	filters, err, _ := compiler.CompileIntoFilter()
+	if err != nil {
+		t.Fatal(diagnostics.Render(err))
+	}
+
+	for _, filter := range filters {
+		if filter.Strictness != model2.StrictnessStrict {
+			continue
+		}
+		var blocks [][]string
+		for _, block := range filter.Blocks {
+			if block.Origin.Source == "fallback" {
+				continue
+			}
+			// Line is 1-based, so it indexes the line after the block's Show/Hide keyword. The test styles set no properties.
+			var conditions []string
+			for _, line := range filter.Lines[block.Line:] {
+				if !strings.HasPrefix(line, "\t") {
+					break
+				}
+				conditions = append(conditions, strings.TrimSpace(line))
+			}
+			blocks = append(blocks, conditions)
+		}
+		return blocks
+	}
+	t.Fatal("no STRICT filter compiled")
+	return nil
+}
+
+func assertBlocks(t *testing.T, got, want [][]string) {
+	t.Helper()
+	if !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("blocks = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,159 @@
+package compilation
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// ConditionalContext is what the conditions of IF blocks are evaluated against.
+type ConditionalContext struct {
+	// Build is the BUILD of the script's metadata.
+	Build string
+	// Strictness is the level of the filter being compiled.
+	Strictness model2.Strictness
+	// Defines are the values given on the command line with `-D name=value`, by name.
+	// A define that is not given compares as an empty string.
+	Defines map[string]string
+	// Warnings receives a warning for each define a condition refers to that is not given, e.g. a misspelled name; nil to skip them.
+	Warnings *model2.Warnings
+}
+
+// ResolveConditionals returns the tree with every IF block replaced by the content of the branch its condition selects.
+// The tree must be post-processed.
+func ResolveConditionals(tree *shared.ParseTree[symbols.LexingTokenType], context ConditionalContext) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	resolved := &shared.ParseTree[symbols.LexingTokenType]{
+		Symbol:   tree.Symbol,
+		Token:    tree.Token,
+		Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0, len(tree.Children)),
+	}
+
+	for _, child := range tree.Children {
+		if child.Symbol != symbols.ParseSymbolConditional.String() {
+			resolvedChild, err := ResolveConditionals(child, context)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Children = append(resolved.Children, resolvedChild)
+			continue
+		}
+
+		branch, err := selectBranch(child, context)
+		if err != nil {
+			return nil, err
+		}
+
+		// The branch takes the place of the IF block, so it is resolved as part of the same parent.
+		resolvedBranch, err := ResolveConditionals(&shared.ParseTree[symbols.LexingTokenType]{Symbol: tree.Symbol, Children: branch}, context)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Children = append(resolved.Children, resolvedBranch.Children...)
+	}
+	return resolved, nil
+}
+
+// selectBranch returns the content of the IF block's branch that applies. An `ELSE IF` is returned as a nested IF block.
+func selectBranch(
+	conditional *shared.ParseTree[symbols.LexingTokenType],
+	context ConditionalContext,
+) ([]*shared.ParseTree[symbols.LexingTokenType], error) {
+	var condition, elseBranch *shared.ParseTree[symbols.LexingTokenType]
+	var body []*shared.ParseTree[symbols.LexingTokenType]
+	for _, child := range conditional.Children {
+		switch child.Symbol {
+		case symbols.ParseSymbolKeyword.String():
+		case symbols.ParseSymbolCompileCondition.String():
+			condition = child
+		case symbols.ParseSymbolElseBranch.String():
+			elseBranch = child
+		default:
+			body = child.Children
+		}
+	}
+
+	holds, err := evaluateCompileCondition(condition, context)
+	if err != nil {
+		return nil, err
+	}
+	if holds {
+		return body, nil
+	}
+	if elseBranch == nil {
+		return nil, nil
+	}
+
+	for _, child := range elseBranch.Children {
+		switch child.Symbol {
+		case symbols.ParseSymbolKeyword.String():
+		case symbols.ParseSymbolConditional.String():
+			return []*shared.ParseTree[symbols.LexingTokenType]{child}, nil
+		default:
+			return child.Children, nil
+		}
+	}
+	return nil, nil
+}
+
+// evaluateCompileCondition evaluates a condition like `BUILD == "WITCH"`, `STRICTNESS >= "STRICT"` or `$league == "hc"`.
+func evaluateCompileCondition(condition *shared.ParseTree[symbols.LexingTokenType], context ConditionalContext) (bool, error) {
+	subject, operatorNode, valueNode := condition.Children[0], condition.Children[1], condition.Children[2]
+	operator := operatorNode.Token.ValueToString()
+	value := valueNode.Token.ValueToString()
+
+	switch subject.Token.Type {
+	case symbols.StrictnessKeywordToken:
+		level, err := model2.ParseStrictness(strings.ToUpper(value))
+		if err != nil {
+			return false, diagnostics.Errorf(valueNode.Position(), "%w", err)
+		}
+		return compareOrdered(int(context.Strictness), int(level), operator), nil
+	case symbols.BuildKeywordToken:
+		if operator != "==" && operator != "!=" {
+			return false, diagnostics.Errorf(operatorNode.Position(), "BUILD can only be compared with == and !=, got %s", operator)
+		}
+		return strings.EqualFold(context.Build, value) == (operator == "=="), nil
+	default:
+		name := strings.TrimPrefix(subject.Token.ValueToString(), "$")
+		defined, given := context.Defines[name]
+		if !given && context.Warnings != nil {
+			context.Warnings.Printf("%s at %s is not defined, so it compares as an empty string (define it with -D %s=value)",
+				subject.Token.ValueToString(), subject.Position(), name)
+		}
+		if operator == "==" || operator == "!=" {
+			return (defined == value) == (operator == "=="), nil
+		}
+
+		left, leftErr := strconv.Atoi(defined)
+		right, rightErr := strconv.Atoi(value)
+		if leftErr != nil || rightErr != nil {
+			return false, diagnostics.Errorf(operatorNode.Position(), "%s compares numbers, but %s is %q and the value is %q",
+				operator, subject.Token.ValueToString(), defined, value)
+		}
+		return compareOrdered(left, right, operator), nil
+	}
+}
+
+func compareOrdered(left, right int, operator string) bool {
+	switch operator {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	case ">=":
+		return left >= right
+	case "<=":
+		return left <= right
+	case ">":
+		return left > right
+	case "<":
+		return left < right
+	default:
+		// The parser only accepts the operators above.
+		return false
+	}
+}
@@ -0,0 +1,24 @@
+package compilation
+
+import (
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// CompileError is returned when a validated script cannot be turned into a filter.
+type CompileError struct {
+	Err error
+}
+
+func (e *CompileError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CompileError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the location in the source that failed to compile, if known.
+func (e *CompileError) Position() lexshared.Position {
+	return diagnostics.PositionOf(e.Err)
+}
@@ -0,0 +1,229 @@
+package compilation
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// buildExpression converts a FullValueExpression node into an expression.
+// `*` and `/` bind tighter than `+` and `-`, and operators of the same precedence apply from left to right.
+func buildExpression(node *shared.ParseTree[symbols.LexingTokenType]) *model2.Expression {
+	operands, operators := flattenExpression(node)
+
+	// The operands of `*` and `/` are folded into terms first, which are then added and subtracted.
+	terms := []*model2.Expression{operands[0]}
+	var termOperators []*shared.ParseTree[symbols.LexingTokenType]
+	for i, operator := range operators {
+		if text := operator.Token.ValueToString(); text == "*" || text == "/" {
+			last := len(terms) - 1
+			terms[last] = &model2.Expression{Operator: text, Left: terms[last], Right: operands[i+1], Position: operator.Position()}
+			continue
+		}
+		terms = append(terms, operands[i+1])
+		termOperators = append(termOperators, operator)
+	}
+
+	expression := terms[0]
+	for i, operator := range termOperators {
+		expression = &model2.Expression{Operator: operator.Token.ValueToString(), Left: expression, Right: terms[i+1], Position: operator.Position()}
+	}
+	return expression
+}
+
+// EvaluateConditionExpression computes a FullValueExpression node written as a condition value, the way the compiler does:
+// a single number for numeric conditions, a list of values otherwise.
+func EvaluateConditionExpression(node *shared.ParseTree[symbols.LexingTokenType], numeric bool, variables map[string][]string) ([]string, error) {
+	return buildExpression(node).EvaluateValues(numeric, variables)
+}
+
+// flattenExpression returns the operands of the expression in order, with the operators between them.
+// Parenthesized groups and lists are returned as a single operand.
+func flattenExpression(node *shared.ParseTree[symbols.LexingTokenType]) ([]*model2.Expression, []*shared.ParseTree[symbols.LexingTokenType]) {
+	var operands []*model2.Expression
+	var operators []*shared.ParseTree[symbols.LexingTokenType]
+	for _, child := range node.Children {
+		switch child.Symbol {
+		case symbols.ParseSymbolValue.String():
+			operands = append(operands, &model2.Expression{
+				Value:    child.Token.ValueToString(),
+				Quoted:   child.Token.Type == symbols.IdentifierValueToken,
+				Position: child.Position(),
+			})
+		case symbols.ParseSymbolValueList.String():
+			list := &model2.Expression{Position: child.Position()}
+			for _, element := range child.FindAllSymbolNodes(symbols.ParseSymbolValue.String()) {
+				list.List = append(list.List, &model2.Expression{
+					Value:    element.Token.ValueToString(),
+					Quoted:   element.Token.Type == symbols.IdentifierValueToken,
+					Position: element.Position(),
+				})
+			}
+			operands = append(operands, list)
+		case symbols.ParseSymbolExpressionGroup.String():
+			operands = append(operands, buildExpression(child.FindSymbolNode(symbols.ParseSymbolFullValueExpression.String())))
+		case symbols.ParseSymbolOperator.String():
+			operators = append(operators, child)
+		default:
+			nestedOperands, nestedOperators := flattenExpression(child)
+			operands = append(operands, nestedOperands...)
+			operators = append(operators, nestedOperators...)
+		}
+	}
+	return operands, operators
+}
+
+// isList reports whether the expression computes a list: it contains a list or refers to a list variable.
+func isList(node *shared.ParseTree[symbols.LexingTokenType], lists map[string]bool) bool {
+	if len(node.FindAllSymbolNodes(symbols.ParseSymbolValueList.String())) > 0 {
+		return true
+	}
+	for _, value := range node.FindAllSymbolNodes(symbols.ParseSymbolValue.String()) {
+		if value.Token.Type == symbols.VariableReferenceToken && lists[strings.TrimPrefix(value.Token.ValueToString(), "$")] {
+			return true
+		}
+	}
+	return false
+}
+
+// isArithmetic reports whether the expression computes a number rather than combining styles:
+// it uses an operator other than `+` or parentheses, or all its operands are numbers.
+func isArithmetic(node *shared.ParseTree[symbols.LexingTokenType], variables map[string][]string) bool {
+	if len(node.FindAllSymbolNodes(symbols.ParseSymbolExpressionGroup.String())) > 0 {
+		return true
+	}
+	for _, operator := range node.FindAllSymbolNodes(symbols.ParseSymbolOperator.String()) {
+		if operator.Token.ValueToString() != "+" {
+			return true
+		}
+	}
+
+	for _, value := range node.FindAllSymbolNodes(symbols.ParseSymbolValue.String()) {
+		text := value.Token.ValueToString()
+		switch value.Token.Type {
+		case symbols.NumberToken:
+		case symbols.VariableReferenceToken:
+			values := variables[strings.TrimPrefix(text, "$")]
+			if len(values) != 1 {
+				return false
+			}
+			if _, err := strconv.Atoi(values[0]); err != nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// variableEvaluator computes the values of the script's variables, evaluating the variables they refer to first.
+type variableEvaluator struct {
+	declarations map[string]*shared.ParseTree[symbols.LexingTokenType]
+	values       map[string][]string
+	// lists holds the variables that are lists, which other expressions then combine as lists too.
+	lists  map[string]bool
+	failed map[string]bool
+	// evaluating holds the variables being evaluated, outermost first, to detect variables that refer to themselves.
+	evaluating []string
+	problems   diagnostics.List
+}
+
+// errDependencyFailed marks a variable whose value is unknown because a variable it refers to failed; the failure is reported there.
+var errDependencyFailed = errors.New("a variable this one refers to could not be evaluated")
+
+// evaluate computes the values of the variable and records them, or records the problem.
+func (e *variableEvaluator) evaluate(name string) bool {
+	if _, ok := e.values[name]; ok {
+		return true
+	}
+	if e.failed[name] {
+		return false
+	}
+	assignment := e.declarations[name]
+
+	if index := slices.Index(e.evaluating, name); index >= 0 {
+		chain := strings.Join(append(slices.Clone(e.evaluating[index:]), name), " -> ")
+		e.fail(name, diagnostics.Errorf(assignment.Position(), "variable %s refers to itself: %s", name, chain))
+		return false
+	}
+	e.evaluating = append(e.evaluating, name)
+	defer func() { e.evaluating = e.evaluating[:len(e.evaluating)-1] }()
+
+	values, list, err := e.compute(assignment)
+	if err != nil {
+		e.fail(name, err)
+		return false
+	}
+	e.values[name] = values
+	e.lists[name] = list
+	return true
+}
+
+func (e *variableEvaluator) fail(name string, err error) {
+	e.failed[name] = true
+	if errors.Is(err, errDependencyFailed) {
+		return
+	}
+
+	var diagnostic *diagnostics.Diagnostic
+	if !errors.As(err, &diagnostic) {
+		diagnostic = &diagnostics.Diagnostic{Err: err}
+	}
+	e.problems = append(e.problems, diagnostic)
+}
+
+// compute returns the values of an assignment, and whether they form a list, once the variables it refers to are evaluated.
+func (e *variableEvaluator) compute(assignment *shared.ParseTree[symbols.LexingTokenType]) ([]string, bool, error) {
+	valueNodes := assignment.FindAllSymbolNodes(symbols.ParseSymbolValue.String())
+	for _, valueNode := range valueNodes {
+		var references []string
+		switch valueNode.Token.Type {
+		case symbols.VariableReferenceToken:
+			references = []string{strings.TrimPrefix(valueNode.Token.ValueToString(), "$")}
+		case symbols.IdentifierValueToken:
+			references = model2.InterpolatedVariables(valueNode.Token.ValueToString())
+		}
+		for _, reference := range references {
+			if _, declared := e.declarations[reference]; declared && !e.evaluate(reference) {
+				return nil, false, errDependencyFailed
+			}
+		}
+	}
+
+	expression := assignment.FindSymbolNode(symbols.ParseSymbolFullValueExpression.String())
+	if expression != nil && isList(expression, e.lists) {
+		values, err := buildExpression(expression).EvaluateList(e.values)
+		if err != nil {
+			return nil, false, err
+		}
+		return values, true, nil
+	}
+	if expression != nil && isArithmetic(expression, e.values) {
+		number, err := buildExpression(expression).Evaluate(e.values)
+		if err != nil {
+			return nil, false, err
+		}
+		return []string{strconv.Itoa(number)}, false, nil
+	}
+
+	values := make([]string, 0, len(valueNodes))
+	for _, valueNode := range valueNodes {
+		value := valueNode.Token.ValueToString()
+		if valueNode.Token.Type == symbols.IdentifierValueToken {
+			interpolated, err := model2.Interpolate(value, e.values, valueNode.Position())
+			if err != nil {
+				return nil, false, err
+			}
+			value = interpolated
+		}
+		values = append(values, value)
+	}
+	return values, false, nil
+}
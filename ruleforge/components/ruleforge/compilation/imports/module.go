@@ -0,0 +1,154 @@
+package imports
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// module is an imported file with a MODULE header. Its sections are held back until a script USEs them.
+type module struct {
+	name         string
+	path         string
+	sections     map[string]*shared.ParseTree[symbols.LexingTokenType]
+	sectionNames []string
+	used         map[string]lexshared.Position
+}
+
+// IsModule reports whether the tree is a module, i.e. whether its first block is a MODULE header.
+func IsModule(tree *shared.ParseTree[symbols.LexingTokenType]) bool {
+	return moduleHeader(tree) != nil
+}
+
+func moduleHeader(tree *shared.ParseTree[symbols.LexingTokenType]) *shared.ParseTree[symbols.LexingTokenType] {
+	for _, child := range tree.Children {
+		if child.Symbol == symbols.ParseSymbolWhitespace.String() {
+			continue
+		}
+		if child.Symbol == symbols.ParseSymbolModule.String() {
+			return child
+		}
+		return nil
+	}
+	return nil
+}
+
+// splitModule registers the sections of the module and returns the rest of its blocks, which are imported as usual.
+func (s *resolution) splitModule(
+	tree *shared.ParseTree[symbols.LexingTokenType],
+	header *shared.ParseTree[symbols.LexingTokenType],
+	path string,
+) ([]*shared.ParseTree[symbols.LexingTokenType], error) {
+	nameNode := header.FindSymbolNode(symbols.ParseSymbolIdentifier.String())
+	name := nameNode.Token.ValueToString()
+	if existing, ok := s.modules[name]; ok {
+		return nil, diagnostics.Errorf(nameNode.Position(), "module %s is already defined by %s", name, existing.path)
+	}
+
+	imported := &module{
+		name:     name,
+		path:     path,
+		sections: make(map[string]*shared.ParseTree[symbols.LexingTokenType]),
+		used:     make(map[string]lexshared.Position),
+	}
+	var problems diagnostics.List
+	var content []*shared.ParseTree[symbols.LexingTokenType]
+	for _, child := range tree.Children {
+		switch child.Symbol {
+		case symbols.ParseSymbolModule.String():
+		case symbols.ParseSymbolSection.String():
+			sectionName, ok := sectionName(child)
+			if !ok {
+				// The section validator reports the missing NAME when the module is validated on its own.
+				continue
+			}
+			if _, ok := imported.sections[sectionName]; ok {
+				problems = append(problems, &diagnostics.Diagnostic{
+					Position: child.Position(),
+					Err:      fmt.Errorf("module %s has several sections named %q", name, sectionName),
+				})
+				continue
+			}
+			imported.sections[sectionName] = child
+			imported.sectionNames = append(imported.sectionNames, sectionName)
+		default:
+			content = append(content, child)
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	s.modules[name] = imported
+	return content, nil
+}
+
+// useSections returns the sections named by a USE statement, in the order they are named.
+func (s *resolution) useSections(use *shared.ParseTree[symbols.LexingTokenType]) ([]*shared.ParseTree[symbols.LexingTokenType], error) {
+	nameNode := use.FindSymbolNode(symbols.ParseSymbolIdentifier.String())
+	name := nameNode.Token.ValueToString()
+	imported, ok := s.modules[name]
+	if !ok {
+		return nil, diagnostics.Errorf(nameNode.Position(), "module %s is not imported, IMPORT it before using its sections", name)
+	}
+
+	var problems diagnostics.List
+	var sections []*shared.ParseTree[symbols.LexingTokenType]
+	for _, valueNode := range use.FindAllSymbolNodes(symbols.ParseSymbolValue.String()) {
+		sectionName := valueNode.Token.ValueToString()
+		section, ok := imported.sections[sectionName]
+		if !ok {
+			problems = append(problems, &diagnostics.Diagnostic{
+				Position: valueNode.Position(),
+				Err: fmt.Errorf("module %s has no section %q, it has %s",
+					name, sectionName, describeSectionNames(imported.sectionNames)),
+			})
+			continue
+		}
+		if previous, ok := imported.used[sectionName]; ok {
+			problems = append(problems, &diagnostics.Diagnostic{
+				Position: valueNode.Position(),
+				Err:      fmt.Errorf("section %q of module %s is already used at %s", sectionName, name, previous),
+			})
+			continue
+		}
+		imported.used[sectionName] = valueNode.Position()
+		sections = append(sections, section)
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return sections, nil
+}
+
+// sectionName returns the NAME given in the METADATA of the section.
+func sectionName(section *shared.ParseTree[symbols.LexingTokenType]) (string, bool) {
+	metadata := section.FindSymbolNode(symbols.ParseSymbolSectionMetadata.String())
+	if metadata == nil {
+		return "", false
+	}
+	for _, assignment := range metadata.FindAllSymbolNodes(symbols.ParseSymbolAssignment.String()) {
+		key := assignment.FindSymbolNode(symbols.ParseSymbolKey.String())
+		value := assignment.FindSymbolNode(symbols.ParseSymbolValue.String())
+		if key != nil && value != nil && key.Token.Type == symbols.NameKeywordToken {
+			return value.Token.ValueToString(), true
+		}
+	}
+	return "", false
+}
+
+func describeSectionNames(names []string) string {
+	if len(names) == 0 {
+		return "no named sections"
+	}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return strings.Join(quoted, ", ")
+}
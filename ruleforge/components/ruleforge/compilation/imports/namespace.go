@@ -0,0 +1,81 @@
+package imports
+
+import (
+	"strings"
+
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// namespaceVariables prefixes the variables declared in the tree with the alias, so `var style` becomes `lib.style`
+// and is referenced as `$lib.style`. References within the tree are renamed along with them.
+func namespaceVariables(tree *shared.ParseTree[symbols.LexingTokenType], alias string) *shared.ParseTree[symbols.LexingTokenType] {
+	declared := make(map[string]bool)
+	for _, variable := range tree.FindAllSymbolNodes(symbols.ParseSymbolVariable.String()) {
+		for _, identifier := range variable.FindAllSymbolNodes(symbols.ParseSymbolIdentifier.String()) {
+			declared[identifier.Token.ValueToString()] = true
+		}
+	}
+	if len(declared) == 0 {
+		return tree
+	}
+	return renameVariables(tree, alias+".", declared)
+}
+
+func renameVariables(
+	node *shared.ParseTree[symbols.LexingTokenType],
+	prefix string,
+	declared map[string]bool,
+) *shared.ParseTree[symbols.LexingTokenType] {
+	renamed := &shared.ParseTree[symbols.LexingTokenType]{
+		Symbol:   node.Symbol,
+		Token:    node.Token,
+		Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0, len(node.Children)),
+	}
+
+	if node.Token != nil {
+		name := node.Token.ValueToString()
+		switch {
+		case node.Symbol == symbols.ParseSymbolIdentifier.String() && node.Token.Type == symbols.IdentifierKeyToken && declared[name]:
+			renamed.Token = withValue(node.Token, prefix+name)
+		case node.Symbol == symbols.ParseSymbolValue.String() && node.Token.Type == symbols.VariableReferenceToken && declared[name[1:]]:
+			renamed.Token = withValue(node.Token, "$"+prefix+name[1:])
+		case node.Token.Type == symbols.IdentifierValueToken:
+			renamed.Token = renameInterpolations(node.Token, prefix, declared)
+		}
+	}
+
+	for _, child := range node.Children {
+		renamed.Children = append(renamed.Children, renameVariables(child, prefix, declared))
+	}
+	return renamed
+}
+
+// renameInterpolations renames the declared variables interpolated into a string, e.g. "T{$tier}" becomes "T{$lib.tier}".
+func renameInterpolations(
+	token *lexshared.Token[symbols.LexingTokenType],
+	prefix string,
+	declared map[string]bool,
+) *lexshared.Token[symbols.LexingTokenType] {
+	text := token.ValueToString()
+	renamed := text
+	for _, name := range model.InterpolatedVariables(text) {
+		if declared[name] {
+			renamed = strings.ReplaceAll(renamed, "{$"+name+"}", "{$"+prefix+name+"}")
+		}
+	}
+	if renamed == text {
+		return token
+	}
+	return withValue(token, renamed)
+}
+
+func withValue(token *lexshared.Token[symbols.LexingTokenType], value string) *lexshared.Token[symbols.LexingTokenType] {
+	return &lexshared.Token[symbols.LexingTokenType]{
+		Type:     token.Type,
+		Value:    []byte(value),
+		Position: token.Position,
+	}
+}
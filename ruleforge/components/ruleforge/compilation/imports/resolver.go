@@ -0,0 +1,286 @@
+// Package imports resolves the IMPORT statements of Ruleforge scripts.
+package imports
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	common_compiler "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// ResolverOptions configures a Resolver.
+type ResolverOptions struct {
+	// SearchPaths are the directories searched, in order, for imports that are not next to the importing file.
+	SearchPaths []string
+}
+
+// Resolver parses scripts and replaces their IMPORT statements with the content of the imported files.
+// Parsed files are cached until they change on disk, so one Resolver should be shared by every script of a run.
+// It is safe for concurrent use.
+type Resolver struct {
+	searchPaths []string
+
+	mu    sync.Mutex
+	cache map[string]cachedFile
+}
+
+// cachedFile is a parsed file, along with the state of the file it was parsed from.
+type cachedFile struct {
+	modTime time.Time
+	size    int64
+	tree    *shared.ParseTree[symbols.LexingTokenType]
+}
+
+func NewResolver() *Resolver {
+	return NewResolverWithOptions(ResolverOptions{})
+}
+
+func NewResolverWithOptions(options ResolverOptions) *Resolver {
+	return &Resolver{
+		searchPaths: options.SearchPaths,
+		cache:       make(map[string]cachedFile),
+	}
+}
+
+// Resolve parses the script and resolves its imports, recursively.
+// Every imported file is included once, where it is first imported, even if several files import it.
+// Besides the tree, it returns the imported files in the order they were read, even if resolving failed.
+func (r *Resolver) Resolve(scriptPath string) (*shared.ParseTree[symbols.LexingTokenType], []string, error) {
+	resolution := &resolution{resolver: r, included: make(map[string]string), modules: make(map[string]*module)}
+	absolutePath, err := filepath.Abs(scriptPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving path %s: %w", scriptPath, err)
+	}
+	resolution.included[absolutePath] = ""
+
+	tree, err := resolution.resolveFile(scriptPath, "")
+	return tree, resolution.dependencies, err
+}
+
+// parse returns the parse tree of the file, from the cache if the file did not change since it was parsed.
+// The returned tree is shared and must not be modified.
+func (r *Resolver) parse(path string) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path %s: %w", path, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening file %s: %w", path, err)
+	}
+
+	r.mu.Lock()
+	cached, ok := r.cache[absolutePath]
+	r.mu.Unlock()
+	if ok && cached.modTime.Equal(info.ModTime()) && cached.size == info.Size() {
+		return cached.tree, nil
+	}
+
+	tree, err := lexAndParse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[absolutePath] = cachedFile{modTime: info.ModTime(), size: info.Size(), tree: tree}
+	r.mu.Unlock()
+	return tree, nil
+}
+
+func lexAndParse(path string) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	handler, err := common_compiler.NewFileHandler(
+		file,
+		rules.GetLexingRules(),
+		rules.GetParsingRules(),
+		symbols.IgnoreToken,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("reading script failed: %w", err)
+	}
+	handler.EnableRecovery(rules.GetRecoveryOptions())
+	if _, err := handler.Lex(); err != nil {
+		return nil, fmt.Errorf("lexing failed: %w", err)
+	}
+	tree, err := handler.Parse()
+	if err != nil {
+		return nil, fmt.Errorf("parsing failed: %w", err)
+	}
+	return tree, nil
+}
+
+// locate finds the imported file: next to the importing file first, then in the search paths.
+// It returns the directories that were searched if the file is not found.
+func (r *Resolver) locate(name string, importingFile string) (string, []string, bool) {
+	if filepath.IsAbs(name) {
+		return name, nil, isFile(name)
+	}
+
+	directories := append([]string{filepath.Dir(importingFile)}, r.searchPaths...)
+	for _, directory := range directories {
+		candidate := filepath.Join(directory, name)
+		if isFile(candidate) {
+			return candidate, nil, true
+		}
+	}
+	return "", directories, false
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+// resolution is the state of resolving the imports of a single script.
+type resolution struct {
+	resolver *Resolver
+	// chain holds the files being resolved, outermost first, to detect import cycles.
+	chain []string
+	// included maps the absolute path of every file included so far to the alias it was included with.
+	included map[string]string
+	// modules holds the imported modules by name, for USE statements.
+	modules      map[string]*module
+	dependencies []string
+}
+
+// resolveFile returns the parse tree of the file with its imports resolved and, given an alias, its variables namespaced.
+func (s *resolution) resolveFile(path string, alias string) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	tree, err := s.resolver.parse(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s.chain = append(s.chain, path)
+	defer func() { s.chain = s.chain[:len(s.chain)-1] }()
+
+	imported := make(map[string]bool)
+	resolved, err := s.resolveNode(tree, path, imported)
+	if err != nil {
+		return nil, err
+	}
+	if alias != "" {
+		resolved = namespaceVariables(resolved, alias)
+	}
+	return resolved, nil
+}
+
+// resolveNode copies the node of the file with every import replaced by the content of the imported file,
+// and every USE statement by the sections it names.
+// imported holds the files the file imports, to report the same file being imported twice.
+func (s *resolution) resolveNode(
+	node *shared.ParseTree[symbols.LexingTokenType],
+	file string,
+	imported map[string]bool,
+) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	resolved := &shared.ParseTree[symbols.LexingTokenType]{
+		Symbol:   node.Symbol,
+		Token:    node.Token,
+		Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0, len(node.Children)),
+	}
+
+	for _, child := range node.Children {
+		switch child.Symbol {
+		case symbols.ParseSymbolImport.String():
+			content, err := s.resolveImport(child, file, imported)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Children = append(resolved.Children, content...)
+		case symbols.ParseSymbolUse.String():
+			sections, err := s.useSections(child)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Children = append(resolved.Children, sections...)
+		default:
+			resolvedChild, err := s.resolveNode(child, file, imported)
+			if err != nil {
+				return nil, err
+			}
+			resolved.Children = append(resolved.Children, resolvedChild)
+		}
+	}
+	return resolved, nil
+}
+
+// resolveImport returns the top-level blocks of the imported file, or nothing if the file is already included.
+// The sections of a module are left out; they are included by USE statements.
+func (s *resolution) resolveImport(
+	node *shared.ParseTree[symbols.LexingTokenType],
+	file string,
+	imported map[string]bool,
+) ([]*shared.ParseTree[symbols.LexingTokenType], error) {
+	nameNode := node.FindSymbolNode(symbols.ParseSymbolValue.String())
+	if nameNode == nil || nameNode.Token == nil {
+		return nil, diagnostics.Errorf(node.Position(), "import is missing a file name")
+	}
+	name := nameNode.Token.ValueToString()
+
+	alias := ""
+	if aliasNode := node.FindSymbolNode(symbols.ParseSymbolIdentifier.String()); aliasNode != nil {
+		alias = aliasNode.Token.ValueToString()
+	}
+
+	path, searched, found := s.resolver.locate(name, file)
+	if !found {
+		return nil, diagnostics.Errorf(nameNode.Position(), "cannot find import %q, searched %s", name, strings.Join(searched, ", "))
+	}
+	absolutePath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, diagnostics.Errorf(nameNode.Position(), "resolving path %s: %w", path, err)
+	}
+
+	if index := slices.IndexFunc(s.chain, func(link string) bool { return sameFile(link, absolutePath) }); index >= 0 {
+		cycle := append(slices.Clone(s.chain[index:]), path)
+		return nil, diagnostics.Errorf(nameNode.Position(), "import cycle: %s", strings.Join(cycle, " -> "))
+	}
+	if imported[absolutePath] {
+		return nil, diagnostics.Errorf(nameNode.Position(), "%q is already imported by this file", name)
+	}
+	imported[absolutePath] = true
+
+	if includedAlias, ok := s.included[absolutePath]; ok {
+		if includedAlias != alias {
+			return nil, diagnostics.Errorf(nameNode.Position(), "%q is already imported %s elsewhere, it cannot also be imported %s",
+				name, describeAlias(includedAlias), describeAlias(alias))
+		}
+		return nil, nil
+	}
+	s.included[absolutePath] = alias
+	s.dependencies = append(s.dependencies, path)
+
+	tree, err := s.resolveFile(path, alias)
+	if err != nil {
+		return nil, err
+	}
+	if header := moduleHeader(tree); header != nil {
+		return s.splitModule(tree, header, path)
+	}
+	return tree.Children, nil
+}
+
+func sameFile(path string, absolutePath string) bool {
+	absolute, err := filepath.Abs(path)
+	return err == nil && absolute == absolutePath
+}
+
+func describeAlias(alias string) string {
+	if alias == "" {
+		return "without an alias"
+	}
+	return "as " + alias
+}
@@ -0,0 +1,104 @@
+package compilation
+
+import (
+	"strconv"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// UnrollLoops returns the tree with every FOR loop replaced by the rules of its body, repeated for each value in order.
+// Within the body, the loop variable is substituted by the value, including within strings like "Tiers/T{$t}".
+// The tree must be post-processed.
+func UnrollLoops(tree *shared.ParseTree[symbols.LexingTokenType]) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	unrolled := &shared.ParseTree[symbols.LexingTokenType]{
+		Symbol:   tree.Symbol,
+		Token:    tree.Token,
+		Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0, len(tree.Children)),
+	}
+
+	for _, child := range tree.Children {
+		if child.Symbol != symbols.ParseSymbolForLoop.String() {
+			unrolledChild, err := UnrollLoops(child)
+			if err != nil {
+				return nil, err
+			}
+			unrolled.Children = append(unrolled.Children, unrolledChild)
+			continue
+		}
+
+		rules, err := unrollLoop(child)
+		if err != nil {
+			return nil, err
+		}
+		unrolled.Children = append(unrolled.Children, rules...)
+	}
+	return unrolled, nil
+}
+
+// unrollLoop returns the rules of the loop's body for each of its values. Nested loops are unrolled as well.
+func unrollLoop(loop *shared.ParseTree[symbols.LexingTokenType]) ([]*shared.ParseTree[symbols.LexingTokenType], error) {
+	var variable string
+	var values []*lexshared.Token[symbols.LexingTokenType]
+	body := &shared.ParseTree[symbols.LexingTokenType]{Symbol: symbols.ParseSymbolRules.String()}
+
+	for _, child := range loop.Children {
+		switch child.Symbol {
+		case symbols.ParseSymbolKey.String():
+			variable = child.Token.ValueToString()
+		case symbols.ParseSymbolValueList.String():
+			for _, valueNode := range child.FindAllSymbolNodes(symbols.ParseSymbolValue.String()) {
+				values = append(values, valueNode.Token)
+			}
+		case symbols.ParseSymbolRange.String():
+			rangeValues, err := loopRangeValues(child)
+			if err != nil {
+				return nil, err
+			}
+			values = rangeValues
+		case symbols.ParseSymbolValue.String():
+			// Loops are unrolled before the variables are evaluated, as IF blocks and imports can still change them.
+			return nil, diagnostics.Errorf(child.Position(), "FOR loops cannot iterate over the variable %s, write its values in the loop instead", child.Token.ValueToString())
+		case symbols.ParseSymbolRules.String():
+			body = child
+		}
+	}
+
+	var rules []*shared.ParseTree[symbols.LexingTokenType]
+	for _, value := range values {
+		iteration := substituteReferences(body, map[string]*lexshared.Token[symbols.LexingTokenType]{variable: value})
+		unrolled, err := UnrollLoops(iteration)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, unrolled.Children...)
+	}
+	return rules, nil
+}
+
+// loopRangeValues returns the numbers of an inclusive range like `1..16`.
+func loopRangeValues(rangeNode *shared.ParseTree[symbols.LexingTokenType]) ([]*lexshared.Token[symbols.LexingTokenType], error) {
+	start, err := strconv.Atoi(rangeNode.Children[0].Token.ValueToString())
+	if err != nil {
+		return nil, diagnostics.Errorf(rangeNode.Children[0].Position(), "invalid range start: %w", err)
+	}
+	end, err := strconv.Atoi(rangeNode.Children[2].Token.ValueToString())
+	if err != nil {
+		return nil, diagnostics.Errorf(rangeNode.Children[2].Position(), "invalid range end: %w", err)
+	}
+	if end < start {
+		return nil, diagnostics.Errorf(rangeNode.Position(), "range %d..%d is empty, the start must not exceed the end", start, end)
+	}
+
+	values := make([]*lexshared.Token[symbols.LexingTokenType], 0, end-start+1)
+	for number := start; number <= end; number++ {
+		values = append(values, &lexshared.Token[symbols.LexingTokenType]{
+			Type:     symbols.NumberToken,
+			Value:    []byte(strconv.Itoa(number)),
+			Position: rangeNode.Position(),
+		})
+	}
+	return values, nil
+}
@@ -0,0 +1,188 @@
+package compilation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// macroDefinition is a macro defined in a script with `DEFINE MACRO`.
+type macroDefinition struct {
+	name       string
+	parameters []string
+	body       []*shared.ParseTree[symbols.LexingTokenType]
+	position   lexshared.Position
+}
+
+// ExpandMacros returns the tree with every invocation of a script-defined macro replaced by the rules of its body,
+// with the parameters substituted by the arguments of the invocation, including within strings like "Tiers/{$tier}".
+// The expanded rules take the place of the invocation, so they are subject to the conditions of the calling section.
+// The definitions themselves are removed. Invocations of built-in macros are left to the rule generator.
+// The tree must be post-processed.
+func ExpandMacros(tree *shared.ParseTree[symbols.LexingTokenType]) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	definitions, err := collectMacroDefinitions(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	expander := &macroExpander{definitions: definitions}
+	return expander.expandNode(tree, nil)
+}
+
+func collectMacroDefinitions(tree *shared.ParseTree[symbols.LexingTokenType]) (map[string]macroDefinition, error) {
+	definitions := make(map[string]macroDefinition)
+	var problems diagnostics.List
+	report := func(position lexshared.Position, format string, args ...any) {
+		problems = append(problems, &diagnostics.Diagnostic{Position: position, Err: fmt.Errorf(format, args...)})
+	}
+
+	for _, node := range tree.FindAllSymbolNodes(symbols.ParseSymbolMacroDefinition.String()) {
+		nameNode := node.FindSymbolNode(symbols.ParseSymbolValue.String())
+		definition := macroDefinition{name: nameNode.Token.ValueToString(), position: nameNode.Position()}
+
+		if slices.Contains(builtInMacros, definition.name) {
+			report(definition.position, "macro %q is a built-in macro and cannot be redefined", definition.name)
+			continue
+		}
+		if existing, ok := definitions[definition.name]; ok {
+			report(definition.position, "macro %q is already defined at %s", definition.name, existing.position)
+			continue
+		}
+
+		if parameterList := node.FindSymbolNode(symbols.ParseSymbolMacroParameters.String()); parameterList != nil {
+			for _, parameter := range parameterList.FindAllSymbolNodes(symbols.ParseSymbolKey.String()) {
+				name := parameter.Token.ValueToString()
+				if slices.Contains(definition.parameters, name) {
+					report(parameter.Position(), "parameter %s of macro %q is declared twice", name, definition.name)
+					continue
+				}
+				definition.parameters = append(definition.parameters, name)
+			}
+		}
+
+		if body := node.FindSymbolNode(symbols.ParseSymbolRules.String()); body != nil {
+			definition.body = body.Children
+		}
+		definitions[definition.name] = definition
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return definitions, nil
+}
+
+type macroExpander struct {
+	definitions map[string]macroDefinition
+}
+
+// expandNode copies node without macro definitions and with the invocations in its rule lists expanded.
+// callers holds the macros being expanded, outermost first, to detect macros that expand into themselves.
+func (e *macroExpander) expandNode(node *shared.ParseTree[symbols.LexingTokenType], callers []string) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	expanded := &shared.ParseTree[symbols.LexingTokenType]{
+		Symbol:   node.Symbol,
+		Token:    node.Token,
+		Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0, len(node.Children)),
+	}
+
+	for _, child := range node.Children {
+		switch {
+		case child.Symbol == symbols.ParseSymbolMacroDefinition.String():
+			continue
+		case node.Symbol == symbols.ParseSymbolRules.String() && e.isDefined(child):
+			rules, err := e.expandInvocation(child, callers)
+			if err != nil {
+				return nil, err
+			}
+			expanded.Children = append(expanded.Children, rules...)
+		default:
+			expandedChild, err := e.expandNode(child, callers)
+			if err != nil {
+				return nil, err
+			}
+			expanded.Children = append(expanded.Children, expandedChild)
+		}
+	}
+	return expanded, nil
+}
+
+func (e *macroExpander) isDefined(node *shared.ParseTree[symbols.LexingTokenType]) bool {
+	if node.Symbol != symbols.ParseSymbolMacroExpression.String() {
+		return false
+	}
+	_, ok := e.definitions[node.Children[1].Token.ValueToString()]
+	return ok
+}
+
+// expandInvocation returns the rules of the invoked macro's body, themselves expanded.
+func (e *macroExpander) expandInvocation(invocation *shared.ParseTree[symbols.LexingTokenType], callers []string) ([]*shared.ParseTree[symbols.LexingTokenType], error) {
+	nameNode := invocation.Children[1]
+	definition := e.definitions[nameNode.Token.ValueToString()]
+
+	if slices.Contains(callers, definition.name) {
+		chain := strings.Join(append(slices.Clone(callers), definition.name), " -> ")
+		return nil, diagnostics.Errorf(nameNode.Position(), "macro %q expands into itself: %s", definition.name, chain)
+	}
+
+	arguments, err := bindMacroArguments(invocation, definition)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &shared.ParseTree[symbols.LexingTokenType]{Symbol: symbols.ParseSymbolRules.String()}
+	for _, rule := range definition.body {
+		body.Children = append(body.Children, substituteReferences(rule, arguments))
+	}
+
+	expandedBody, err := e.expandNode(body, append(slices.Clone(callers), definition.name))
+	if err != nil {
+		return nil, err
+	}
+	return expandedBody.Children, nil
+}
+
+// bindMacroArguments maps every parameter of the macro to the argument of the invocation.
+// Each parameter takes exactly one argument; unknown, repeated and missing arguments are reported together.
+func bindMacroArguments(
+	invocation *shared.ParseTree[symbols.LexingTokenType],
+	definition macroDefinition,
+) (map[string]*lexshared.Token[symbols.LexingTokenType], error) {
+	arguments := make(map[string]*lexshared.Token[symbols.LexingTokenType], len(definition.parameters))
+	var problems diagnostics.List
+	report := func(position lexshared.Position, format string, args ...any) {
+		problems = append(problems, &diagnostics.Diagnostic{Position: position, Err: fmt.Errorf(format, args...)})
+	}
+
+	for _, parameter := range invocation.FindAllSymbolNodes(symbols.ParseSymbolParameter.String()) {
+		key := parameter.Children[1].Token.ValueToString()
+		switch {
+		case !slices.Contains(definition.parameters, key):
+			report(parameter.Position(), "macro %q has no parameter %s", definition.name, key)
+		case arguments[key] != nil:
+			report(parameter.Position(), "parameter %s of macro %q is given twice", key, definition.name)
+		default:
+			arguments[key] = parameter.Children[3].Token
+		}
+	}
+
+	var missing []string
+	for _, parameter := range definition.parameters {
+		if arguments[parameter] == nil {
+			missing = append(missing, parameter)
+		}
+	}
+	if len(missing) > 0 {
+		report(invocation.Children[1].Position(), "macro %q takes %d arguments (%s), missing %s",
+			definition.name, len(definition.parameters), strings.Join(definition.parameters, ", "), strings.Join(missing, ", "))
+	}
+
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return arguments, nil
+}
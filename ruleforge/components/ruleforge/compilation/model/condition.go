@@ -2,60 +2,57 @@ package model
 
 import (
 	"fmt"
-	"log"
 	"slices"
-	"sort"
-)
+	"strings"
 
-var conditionIdentifierToCompiledIdentifier = map[string]string{
-	"@area_level":         "AreaLevel",
-	"@stack_size":         "StackSize",
-	"@item_type":          "BaseType",
-	"@item_class":         "Class",
-	"@rarity":             "Rarity",
-	"@sockets":            "Sockets",
-	"@socket_group":       "SocketGroup",
-	"@height":             "Height",
-	"@width":              "Width",
-	"@map_tier":           "MapTier",
-	"@quality":            "Quality",
-	"@corrupted":          "Corrupted",
-	"@base_armour":        "BaseArmour",
-	"@base_evasion":       "BaseEvasion",
-	"@base_energy_shield": "BaseEnergyShield",
-	"@fractured":          "FracturedItem",
-	"@has_explicit_mod":   "HasExplicitMod",
-	"@identified":         "Identified",
-	"@base_ward":          "BaseWard",
-}
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/conditions"
+)
 
 type Condition struct {
 	Identifier string
 	Operator   string
-	Value      []string
+	// Count turns the condition into PoE's counted form, e.g. `HasExplicitMod >=2 "a" "b"`; empty if not counted.
+	Count string
+	Value []string
+	// Expression is evaluated into the value of the condition, e.g. `$map_start + 5`, or into its values for
+	// conditions taking several, e.g. `$orbs - ["Divine Orb"]`; nil if Value is given.
+	Expression *Expression
+	Position   shared.Position
 }
 
-func debugMap(m map[string][]string) {
-	keys := make([]string, 0, len(m))
-	for k := range m {
-		keys = append(keys, k)
+// ConstructCompiledCondition resolves variables and renders the condition as a PoE filter line.
+func (c *Condition) ConstructCompiledCondition(variables *map[string][]string, validBaseTypes []string, warnings *Warnings) (string, error) {
+	registered, err := lookupIdentifier(c.Identifier)
+	if err != nil {
+		return "", diagnostics.Errorf(c.Position, "%w", err)
 	}
-	sort.Strings(keys)
+	compiledIdentifier := registered.Keyword
+	var compiledValues []string
 
-	// Print in order
-	for _, k := range keys {
-		fmt.Printf("%s => %v\n", k, m[k])
+	if c.Expression != nil {
+		values, err := c.evaluateExpression(registered, *variables)
+		if err != nil {
+			return "", err
+		}
+		for _, value := range values {
+			if compiledIdentifier == "BaseType" {
+				c.validateBaseType(value, validBaseTypes, warnings)
+			}
+		}
+		compiledValues = append(compiledValues, values...)
 	}
-}
-
-func (c *Condition) ConstructCompiledCondition(variables *map[string][]string, validBaseTypes []string) string {
-	compiledIdentifier := compileIdentifier(c.Identifier)
-	var compiledValues []string
 
 	for _, value := range c.Value {
-		if value[0] != '$' {
+		if value == "" || value[0] != '$' {
+			interpolated, err := Interpolate(value, *variables, c.Position)
+			if err != nil {
+				return "", err
+			}
+			value = interpolated
 			if compiledIdentifier == "BaseType" {
-				c.validateBaseType(value, validBaseTypes)
+				c.validateBaseType(value, validBaseTypes, warnings)
 			}
 
 			compiledValues = append(compiledValues, value)
@@ -64,13 +61,12 @@ func (c *Condition) ConstructCompiledCondition(variables *map[string][]string, v
 
 		variableValues, ok := (*variables)[value[1:]]
 
-		if !ok {
-			debugMap(*variables)
-			panic(fmt.Sprintf("variable Value not found in compiled Condition: %s -> %s", c.Identifier, c.Value))
+		if !ok || len(variableValues) == 0 {
+			return "", diagnostics.Errorf(c.Position, "unknown variable %s in condition %s", value, c.Identifier)
 		}
 
 		if compiledIdentifier == "BaseType" {
-			c.validateBaseType(variableValues[0], validBaseTypes)
+			c.validateBaseType(variableValues[0], validBaseTypes, warnings)
 		}
 
 		for _, variableValue := range variableValues {
@@ -78,20 +74,78 @@ func (c *Condition) ConstructCompiledCondition(variables *map[string][]string, v
 		}
 	}
 
-	return c.constructString(compiledIdentifier, c.Operator, compiledValues)
+	// PoE matches values that contain the text when the condition has no operator.
+	operator := c.Operator
+	if operator == conditions.ContainsOperator {
+		operator = ""
+		if compiledIdentifier == "BaseType" {
+			for _, pattern := range compiledValues {
+				c.validateBaseTypePattern(pattern, validBaseTypes, warnings)
+			}
+		}
+	}
+	return c.constructString(compiledIdentifier, operator+c.Count, compiledValues, registered.Kind.Quoted()), nil
+}
+
+// evaluateExpression computes the values of the expression: a number for numeric conditions, a list of values otherwise.
+// The validator only knows the values once the expression is evaluated, so they are checked here.
+func (c *Condition) evaluateExpression(registered conditions.Identifier, variables map[string][]string) ([]string, error) {
+	values, err := c.Expression.EvaluateValues(registered.Kind == conditions.Numeric, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return nil, diagnostics.Errorf(c.Expression.Start(), "the expression leaves %s without values", c.Identifier)
+	}
+	if err := registered.CheckValueCount(len(values)); err != nil {
+		return nil, diagnostics.Errorf(c.Expression.Start(), "%w from the expression", err)
+	}
+	for _, value := range values {
+		if err := registered.CheckValue(value); err != nil {
+			return nil, diagnostics.Errorf(c.Expression.Start(), "%w from the expression", err)
+		}
+	}
+	return values, nil
 }
 
-func (c *Condition) validateBaseType(baseType string, validBaseTypes []string) {
+func (c *Condition) validateBaseType(baseType string, validBaseTypes []string, warnings *Warnings) {
+	// Patterns are checked as a whole once the variables are resolved.
+	if c.Operator == conditions.ContainsOperator {
+		return
+	}
+
 	if !slices.Contains(validBaseTypes, baseType) {
-		log.Printf("WARNING: %s is not a valid BaseType (this could be due to it not being extracted from PoB yet, your game might run fine)", baseType)
+		warnings.Printf("%s at %s is not a valid BaseType (this could be due to it not being extracted from PoB yet, your game might run fine)", baseType, c.Position)
 	}
 }
 
-func (c *Condition) constructString(identifier, operator string, values []string) string {
+// validateBaseTypePattern reports how many known base types contain the pattern, so overly broad or empty patterns stand out.
+func (c *Condition) validateBaseTypePattern(pattern string, validBaseTypes []string, warnings *Warnings) {
+	lowered := strings.ToLower(pattern)
+	matches := 0
+	for _, baseType := range validBaseTypes {
+		if strings.Contains(strings.ToLower(baseType), lowered) {
+			matches++
+		}
+	}
+
+	if matches == 0 {
+		warnings.Printf("BaseType pattern %q at %s matches no known base type (this could be due to it not being extracted from PoB yet, your game might run fine)", pattern, c.Position)
+		return
+	}
+	warnings.Printf("BaseType pattern %q at %s matches %d known base types", pattern, c.Position, matches)
+}
+
+func (c *Condition) constructString(identifier, operator string, values []string, quoted bool) string {
 	valueString := ""
 
 	for _, value := range values {
-		valueString += fmt.Sprintf("\"%s\" ", value)
+		if quoted {
+			valueString += quoteValue(value) + " "
+		} else {
+			valueString += value + " "
+		}
 	}
 
 	if operator != "" {
@@ -101,12 +155,18 @@ func (c *Condition) constructString(identifier, operator string, values []string
 	}
 }
 
-func compileIdentifier(identifier string) string {
-	compiled, ok := conditionIdentifierToCompiledIdentifier[identifier]
+// quoteValue writes the value as a PoE filter string. PoE reads everything up to the next quote literally,
+// so backslashes are written as they are; values holding a quote are rejected by the identifier's value check.
+func quoteValue(value string) string {
+	return "\"" + value + "\""
+}
+
+func lookupIdentifier(identifier string) (conditions.Identifier, error) {
+	registered, ok := conditions.Lookup(identifier)
 
-	if !ok {
-		panic("invalid Identifier: " + identifier)
+	if !ok || registered.Macro {
+		return conditions.Identifier{}, fmt.Errorf("invalid Identifier: %s", identifier)
 	}
 
-	return compiled
+	return registered, nil
 }
@@ -0,0 +1,81 @@
+package model
+
+// LogicalOperator combines the operands of a ConditionExpression.
+type LogicalOperator string
+
+const (
+	// LogicalAnd is written `->` in scripts.
+	LogicalAnd LogicalOperator = "->"
+	// LogicalOr is written `|` in scripts.
+	LogicalOr LogicalOperator = "|"
+)
+
+// ConditionExpression is a WHERE clause: conditions combined with AND and OR, possibly grouped in parentheses.
+// A leaf holds a single Condition; otherwise its Operands are combined by Operator.
+// The zero value is an empty AND, which always holds.
+type ConditionExpression struct {
+	Condition *Condition
+	Operator  LogicalOperator
+	Operands  []ConditionExpression
+}
+
+// ConditionLeaf returns the expression holding a single condition.
+func ConditionLeaf(condition Condition) ConditionExpression {
+	return ConditionExpression{Condition: &condition}
+}
+
+// AllOf returns the expression that holds when all the given conditions hold.
+func AllOf(conditions ...Condition) ConditionExpression {
+	operands := make([]ConditionExpression, len(conditions))
+	for i, condition := range conditions {
+		operands[i] = ConditionLeaf(condition)
+	}
+	return ConditionExpression{Operator: LogicalAnd, Operands: operands}
+}
+
+// IsEmpty reports whether the expression holds no conditions at all.
+func (e ConditionExpression) IsEmpty() bool {
+	if e.Condition != nil {
+		return false
+	}
+	for _, operand := range e.Operands {
+		if !operand.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+// Disjunction expands the expression into disjunctive normal form:
+// the expression holds when all conditions of any one of the returned conjunctions hold.
+// An expression without conditions yields a single empty conjunction.
+func (e ConditionExpression) Disjunction() [][]Condition {
+	if e.Condition != nil {
+		return [][]Condition{{*e.Condition}}
+	}
+
+	if e.Operator == LogicalOr {
+		var conjunctions [][]Condition
+		for _, operand := range e.Operands {
+			conjunctions = append(conjunctions, operand.Disjunction()...)
+		}
+		return conjunctions
+	}
+
+	// AND distributes over the alternatives of its operands.
+	conjunctions := [][]Condition{{}}
+	for _, operand := range e.Operands {
+		alternatives := operand.Disjunction()
+		combined := make([][]Condition, 0, len(conjunctions)*len(alternatives))
+		for _, conjunction := range conjunctions {
+			for _, alternative := range alternatives {
+				merged := make([]Condition, 0, len(conjunction)+len(alternative))
+				merged = append(merged, conjunction...)
+				merged = append(merged, alternative...)
+				combined = append(combined, merged)
+			}
+		}
+		conjunctions = combined
+	}
+	return conjunctions
+}
@@ -0,0 +1,214 @@
+package model
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// Expression is a number or a list of values computed at compile time, e.g. `$map_start + 5` or `$orbs - ["Divine Orb"]`.
+// A leaf holds a number, a quoted string, a variable reference or a list; any other node applies Operator to Left and Right.
+type Expression struct {
+	Operator    string
+	Left, Right *Expression
+	// Value is the text of a leaf: a number, a string or a reference like `$map_start`.
+	Value string
+	// Quoted is set for leaves that are quoted strings, which are only accepted if they hold a number.
+	Quoted bool
+	// List holds the elements of a list leaf like `["Exalted Orb", $orbs]`, each a leaf itself.
+	List     []*Expression
+	Position shared.Position
+}
+
+// Evaluate computes the expression. Variables must hold a single number; division rounds towards zero.
+func (e *Expression) Evaluate(variables map[string][]string) (int, error) {
+	if e.Operator == "" {
+		return e.evaluateLeaf(variables)
+	}
+
+	left, err := e.Left.Evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+	right, err := e.Right.Evaluate(variables)
+	if err != nil {
+		return 0, err
+	}
+
+	switch e.Operator {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	case "/":
+		if right == 0 {
+			return 0, diagnostics.Errorf(e.Right.Position, "division by zero")
+		}
+		return left / right, nil
+	default:
+		return 0, diagnostics.Errorf(e.Position, "unknown operator %s", e.Operator)
+	}
+}
+
+// Start returns the position of the first operand, where the expression begins.
+func (e *Expression) Start() shared.Position {
+	if e.Operator == "" {
+		return e.Position
+	}
+	return e.Left.Start()
+}
+
+func (e *Expression) evaluateLeaf(variables map[string][]string) (int, error) {
+	if e.List != nil {
+		return 0, diagnostics.Errorf(e.Position, "a list cannot be used in arithmetic, which needs numbers")
+	}
+	if e.Quoted || !strings.HasPrefix(e.Value, "$") {
+		text, err := Interpolate(e.Value, variables, e.Position)
+		if err != nil {
+			return 0, err
+		}
+		number, err := strconv.Atoi(text)
+		if err != nil {
+			return 0, diagnostics.Errorf(e.Position, "%q is a string, arithmetic needs numbers", text)
+		}
+		return number, nil
+	}
+
+	values, ok := variables[e.Value[1:]]
+	if !ok {
+		return 0, diagnostics.Errorf(e.Position, "unknown variable %s", e.Value)
+	}
+	if len(values) != 1 {
+		return 0, diagnostics.Errorf(e.Position, "%s holds %d values, arithmetic needs a single number", e.Value, len(values))
+	}
+	number, err := strconv.Atoi(values[0])
+	if err != nil {
+		return 0, diagnostics.Errorf(e.Position, "%s is %q, arithmetic needs numbers", e.Value, values[0])
+	}
+	return number, nil
+}
+
+// EvaluateValues computes the values the expression gives a condition: a single number if numeric, a list of values otherwise.
+func (e *Expression) EvaluateValues(numeric bool, variables map[string][]string) ([]string, error) {
+	if !numeric {
+		return e.EvaluateList(variables)
+	}
+
+	number, err := e.Evaluate(variables)
+	if err != nil {
+		return nil, err
+	}
+	return []string{strconv.Itoa(number)}, nil
+}
+
+// EvaluateList computes the values of a list expression: `+` appends the values of its right operand and `-` removes them.
+// Strings count as lists of one value, and variables stand for all their values. Numbers cannot be combined with lists.
+func (e *Expression) EvaluateList(variables map[string][]string) ([]string, error) {
+	if e.Operator == "" {
+		return e.evaluateListLeaf(variables)
+	}
+
+	for _, operand := range []*Expression{e.Left, e.Right} {
+		if operand.isNumber() {
+			return nil, diagnostics.Errorf(operand.Position, "%s is a number, lists can only be combined with lists and strings", operand.Value)
+		}
+	}
+
+	left, err := e.Left.EvaluateList(variables)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.Right.EvaluateList(variables)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.Operator {
+	case "+":
+		return append(left, right...), nil
+	case "-":
+		return slices.DeleteFunc(left, func(value string) bool {
+			return slices.Contains(right, value)
+		}), nil
+	default:
+		return nil, diagnostics.Errorf(e.Position, "lists can only be combined with + and -, not %s", e.Operator)
+	}
+}
+
+// isNumber reports whether the expression is a number written as is, e.g. `3`.
+func (e *Expression) isNumber() bool {
+	return e.Operator == "" && e.List == nil && !e.Quoted && !strings.HasPrefix(e.Value, "$")
+}
+
+func (e *Expression) evaluateListLeaf(variables map[string][]string) ([]string, error) {
+	if e.List != nil {
+		values := make([]string, 0, len(e.List))
+		for _, element := range e.List {
+			elementValues, err := element.evaluateListLeaf(variables)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elementValues...)
+		}
+		return values, nil
+	}
+
+	if e.Quoted || !strings.HasPrefix(e.Value, "$") {
+		text, err := Interpolate(e.Value, variables, e.Position)
+		if err != nil {
+			return nil, err
+		}
+		return []string{text}, nil
+	}
+
+	values, ok := variables[e.Value[1:]]
+	if !ok {
+		return nil, diagnostics.Errorf(e.Position, "unknown variable %s", e.Value)
+	}
+	return slices.Clone(values), nil
+}
+
+var interpolation = regexp.MustCompile(`\{\$([A-Za-z0-9_.]+)\}`)
+
+// Interpolate replaces every `{$name}` within the text by the value of the variable, e.g. "Tiers/T{$n}" by "Tiers/T5".
+// The variable must hold a single value.
+func Interpolate(text string, variables map[string][]string, position shared.Position) (string, error) {
+	if !strings.Contains(text, "{$") {
+		return text, nil
+	}
+
+	var problem error
+	interpolated := interpolation.ReplaceAllStringFunc(text, func(match string) string {
+		name := interpolation.FindStringSubmatch(match)[1]
+		values, ok := variables[name]
+		switch {
+		case problem != nil:
+		case !ok:
+			problem = diagnostics.Errorf(position, "unknown variable $%s in %q", name, text)
+		case len(values) != 1:
+			problem = diagnostics.Errorf(position, "$%s holds %d values and cannot be interpolated into %q", name, len(values), text)
+		default:
+			return values[0]
+		}
+		return match
+	})
+	if problem != nil {
+		return "", problem
+	}
+	return interpolated, nil
+}
+
+// InterpolatedVariables returns the names of the variables interpolated into the text, e.g. n for "Tiers/T{$n}".
+func InterpolatedVariables(text string) []string {
+	var names []string
+	for _, match := range interpolation.FindAllStringSubmatch(text, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
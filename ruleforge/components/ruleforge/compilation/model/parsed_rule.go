@@ -5,7 +5,7 @@ import "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config
 type ParsedRule struct {
 	Style          *config.Style
 	Action         RuleType
-	Conditions     []Condition
+	Conditions     ConditionExpression
 	Variables      *map[string][]string
 	ValidBaseTypes []string
 }
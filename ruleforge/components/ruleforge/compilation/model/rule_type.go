@@ -5,4 +5,19 @@ type RuleType string
 const (
 	ShowRule RuleType = "Show"
 	HideRule RuleType = "Hide"
+	// ContinueRule shows the item and lets later rules match it too, so it only adds the style properties it sets.
+	ContinueRule RuleType = "Continue"
 )
+
+// Visibility returns the block keyword of the rule type: Show or Hide.
+func (r RuleType) Visibility() RuleType {
+	if r == ContinueRule {
+		return ShowRule
+	}
+	return r
+}
+
+// Continues reports whether items matched by the rule fall through to later rules.
+func (r RuleType) Continues() bool {
+	return r == ContinueRule
+}
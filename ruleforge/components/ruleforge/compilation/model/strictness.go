@@ -0,0 +1,39 @@
+package model
+
+import "fmt"
+
+// Strictness is the ordered strictness level of a filter or a rule.
+// A rule marked with a strictness only applies to filters of that level or stricter.
+type Strictness int
+
+const (
+	StrictnessAll Strictness = iota
+	StrictnessSoft
+	StrictnessSemiStrict
+	StrictnessStrict
+	StrictnessSuperStrict
+)
+
+var strictnessNames = []string{"ALL", "SOFT", "SEMI-STRICT", "STRICT", "SUPER-STRICT"}
+
+// AllStrictnessLevels returns every strictness level, from least to most strict.
+func AllStrictnessLevels() []Strictness {
+	return []Strictness{StrictnessAll, StrictnessSoft, StrictnessSemiStrict, StrictnessStrict, StrictnessSuperStrict}
+}
+
+// ParseStrictness converts a strictness keyword (e.g. "SEMI-STRICT") into its level.
+func ParseStrictness(value string) (Strictness, error) {
+	for i, name := range strictnessNames {
+		if name == value {
+			return Strictness(i), nil
+		}
+	}
+	return StrictnessAll, fmt.Errorf("invalid strictness value %q", value)
+}
+
+func (s Strictness) String() string {
+	if s < 0 || int(s) >= len(strictnessNames) {
+		return fmt.Sprintf("Strictness(%d)", int(s))
+	}
+	return strictnessNames[s]
+}
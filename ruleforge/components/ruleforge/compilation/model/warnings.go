@@ -0,0 +1,31 @@
+package model
+
+import (
+	"fmt"
+	"log"
+)
+
+// Warnings logs each distinct warning once. The script is compiled once per strictness level,
+// so without it every check of a condition would report the same warning for every level.
+type Warnings struct {
+	logger *log.Logger
+	seen   map[string]bool
+}
+
+// NewWarnings creates a Warnings that logs to the given logger.
+func NewWarnings(logger *log.Logger) *Warnings {
+	return &Warnings{
+		logger: logger,
+		seen:   make(map[string]bool),
+	}
+}
+
+// Printf logs the warning, unless the same warning was logged before.
+func (w *Warnings) Printf(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	if w.seen[message] {
+		return
+	}
+	w.seen[message] = true
+	w.logger.Print("WARNING: " + message)
+}
@@ -6,19 +6,31 @@ import (
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
 )
 
+// continueKeyword makes PoE keep evaluating later blocks for items the block matched.
+const continueKeyword = "Continue"
+
 type RuleFactory struct{}
 
-func (r *RuleFactory) ConstructRule(ruleType model.RuleType, style config.Style, conditions []string) []string {
-	output := []string{string(ruleType)}
+// CompiledRule is a rendered filter block together with the type of the rule it was constructed from.
+type CompiledRule struct {
+	Type  model.RuleType
+	Lines []string
+}
+
+func (r *RuleFactory) ConstructRule(ruleType model.RuleType, style config.Style, conditions []string) CompiledRule {
+	output := []string{string(ruleType.Visibility())}
 
 	for _, condition := range conditions {
 		output = append(output, r.prefixLineWithTab(condition))
 	}
 
 	output = append(output, r.transformStyleIntoText(style)...)
+	if ruleType.Continues() {
+		output = append(output, r.prefixLineWithTab(continueKeyword))
+	}
 	output = append(output, "")
 
-	return output
+	return CompiledRule{Type: ruleType, Lines: output}
 }
 
 func (r *RuleFactory) transformStyleIntoText(style config.Style) []string {
@@ -1,16 +1,21 @@
 package compilation
 
 import (
+	"errors"
 	"fmt"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/data_generation"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/data_generation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/conditions"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 	"log"
+	"maps"
 	"slices"
 	"sort"
+	"strings"
 )
 
 // RuleGenerator is the engine for compiling rules. It contains all complex game logic.
@@ -28,6 +33,9 @@ type RuleGenerator struct {
 	chasePotentialWeight  float64
 	baseTypeData          []config.BaseTypeAutomationEntry
 	build                 *Build
+	strictness            model2.Strictness
+	log                   *log.Logger
+	warnings              *model2.Warnings
 }
 
 // NewRuleGenerator creates the rule generation engine.
@@ -45,6 +53,9 @@ func NewRuleGenerator(
 	chasePotentialWeight float64,
 	baseTypeData []config.BaseTypeAutomationEntry,
 	build *Build,
+	strictness model2.Strictness,
+	logger *log.Logger,
+	warnings *model2.Warnings,
 ) *RuleGenerator {
 	sort.Slice(armors, func(i, j int) bool {
 		itemA := armors[i]
@@ -93,57 +104,141 @@ func NewRuleGenerator(
 		chasePotentialWeight:  chasePotentialWeight,
 		baseTypeData:          baseTypeData,
 		build:                 build,
+		strictness:            strictness,
+		log:                   logger,
+		warnings:              warnings,
 	}
 }
 
+// GeneratedBlock is a compiled filter block together with the script construct it came from.
+type GeneratedBlock struct {
+	Lines  []string
+	Origin BlockOrigin
+}
+
 // GenerateRulesForSection compiles all rules within a single logical section.
 func (rg *RuleGenerator) GenerateRulesForSection(
 	section ExtractedSection,
 	variables map[string][]string,
-) ([][]string, error) {
-	var allGeneratedRules [][]string
+) ([]GeneratedBlock, error) {
+	var allGeneratedRules []GeneratedBlock
+
+	sectionStrictness := model2.StrictnessAll
+	if section.Strictness != "" {
+		parsed, err := model2.ParseStrictness(section.Strictness)
+		if err != nil {
+			return nil, fmt.Errorf("section %q: %w", section.Name, err)
+		}
+		sectionStrictness = parsed
+	}
 
 	for _, childNode := range section.RuleNodes {
-		var generatedRules [][]string
+		var generatedRules []CompiledRule
 		var err error
 
+		ruleStrictness, err := extractRuleStrictness(childNode, sectionStrictness)
+		if err != nil {
+			return nil, err
+		}
+
 		switch childNode.Symbol {
 		case symbols.ParseSymbolRuleExpression.String():
 			generatedRules, err = rg.handleRuleExpression(childNode, &variables, section.Conditions)
 		case symbols.ParseSymbolMacroExpression.String():
 			generatedRules, err = rg.handleMacroExpression(childNode, &variables, section.Conditions)
 		default:
-			return nil, fmt.Errorf("unsupported symbol in rule list: %s", childNode.Symbol)
+			return nil, diagnostics.Errorf(childNode.Position(), "unsupported symbol in rule list: %s", childNode.Symbol)
 		}
 
 		if err != nil {
-			return nil, err
+			return nil, errorAtNode(childNode, err)
+		}
+
+		origin := blockOriginOf(section.Name, childNode)
+		for _, rule := range rg.applyStrictness(generatedRules, ruleStrictness) {
+			allGeneratedRules = append(allGeneratedRules, GeneratedBlock{Lines: rule.Lines, Origin: origin})
 		}
-		allGeneratedRules = append(allGeneratedRules, generatedRules...)
 	}
 	return allGeneratedRules, nil
 }
 
+// blockOriginOf describes the rule or macro node the blocks of a section were generated from.
+func blockOriginOf(sectionName string, node *shared.ParseTree[symbols.LexingTokenType]) BlockOrigin {
+	origin := BlockOrigin{Section: sectionName, Position: node.Position()}
+	if node.Symbol == symbols.ParseSymbolMacroExpression.String() {
+		origin.Source = fmt.Sprintf("macro %q", node.Children[1].Token.ValueToString())
+		return origin
+	}
+
+	origin.Source = "rule"
+	origin.Style = node.Children[2].Token.ValueToString()
+	return origin
+}
+
+// errorAtNode attaches the node's source position to err, unless err already carries one.
+func errorAtNode(node *shared.ParseTree[symbols.LexingTokenType], err error) error {
+	var diagnostic *diagnostics.Diagnostic
+	if errors.As(err, &diagnostic) {
+		return err
+	}
+	return diagnostics.Errorf(node.Position(), "%w", err)
+}
+
+// extractRuleStrictness returns the strictness from a rule's `#LEVEL` indicator,
+// falling back to the section's strictness when the rule has none.
+func extractRuleStrictness(
+	ruleNode *shared.ParseTree[symbols.LexingTokenType],
+	sectionStrictness model2.Strictness,
+) (model2.Strictness, error) {
+	for i, child := range ruleNode.Children {
+		if child.Token == nil || child.Token.Type != symbols.RuleStrictnessIndicatorToken || i+1 >= len(ruleNode.Children) {
+			continue
+		}
+		strictness, err := model2.ParseStrictness(ruleNode.Children[i+1].Token.ValueToString())
+		if err != nil {
+			return strictness, diagnostics.Errorf(ruleNode.Children[i+1].Position(), "%w", err)
+		}
+		return strictness, nil
+	}
+	return sectionStrictness, nil
+}
+
+// applyStrictness adapts compiled rules to the filter's strictness level.
+// Rules stricter than the filter are inactive: Show rules become Hide rules, Hide and Continue rules are dropped.
+func (rg *RuleGenerator) applyStrictness(compiledRules []CompiledRule, ruleStrictness model2.Strictness) []CompiledRule {
+	if ruleStrictness <= rg.strictness {
+		return compiledRules
+	}
+
+	adapted := make([]CompiledRule, 0, len(compiledRules))
+	for _, rule := range compiledRules {
+		if rule.Type.Continues() || rule.Type == model2.HideRule {
+			continue
+		}
+		hidden := CompiledRule{Type: model2.HideRule, Lines: slices.Clone(rule.Lines)}
+		hidden.Lines[0] = string(model2.HideRule)
+		adapted = append(adapted, hidden)
+	}
+	return adapted
+}
+
 func (rg *RuleGenerator) handleRuleExpression(
 	ruleExpressionNode *shared.ParseTree[symbols.LexingTokenType],
 	variables *map[string][]string,
-	sectionConditions []model2.Condition,
-) ([][]string, error) {
+	sectionConditions model2.ConditionExpression,
+) ([]CompiledRule, error) {
 	styleValue := ruleExpressionNode.Children[2].Token.ValueToString()
 	style, err := rg.styleManager.GetStyle(styleValue)
 	if err != nil {
-		return nil, err
+		return nil, diagnostics.Errorf(ruleExpressionNode.Children[2].Position(), "%w", err)
 	}
 
-	showOrHideStr := ruleExpressionNode.Children[4].Token.ValueToString()[1:]
-	var action model2.RuleType
-	if showOrHideStr == "Show" {
-		action = model2.ShowRule
-	} else {
-		action = model2.HideRule
+	action, err := ruleAction(ruleExpressionNode.Children[4])
+	if err != nil {
+		return nil, err
 	}
 
-	ruleSpecificConditions := retrieveConditions(ruleExpressionNode)
+	ruleSpecificConditions := retrieveConditionExpression(ruleExpressionNode.Children[0])
 
 	rule := &model2.ParsedRule{
 		Style:          style,
@@ -153,14 +248,39 @@ func (rg *RuleGenerator) handleRuleExpression(
 		ValidBaseTypes: rg.validBaseTypes,
 	}
 
-	return rg.compileParsedRule(rule, sectionConditions), nil
+	return rg.compileParsedRule(rule, sectionConditions)
+}
+
+// ruleAction converts the action of a rule: `$Show`, `$Hide`, `$Continue` or `$Show+Continue`.
+func ruleAction(actionNode *shared.ParseTree[symbols.LexingTokenType]) (model2.RuleType, error) {
+	if actionNode.Symbol == symbols.ParseSymbolRuleAction.String() {
+		if shown := actionNode.Children[0].Token.ValueToString(); shown != "$Show" {
+			return "", diagnostics.Errorf(actionNode.Position(), "only $Show can continue, got %s+Continue", shown)
+		}
+		return model2.ContinueRule, nil
+	}
+
+	switch actionNode.Token.ValueToString()[1:] {
+	case "Show":
+		return model2.ShowRule, nil
+	case "Continue":
+		return model2.ContinueRule, nil
+	default:
+		return model2.HideRule, nil
+	}
+}
+
+// builtInMacros are the macros implemented by handleMacroExpression. Scripts cannot define macros with these names.
+var builtInMacros = []string{
+	"item_progression-equipment-leveling", "item_progression-equipment-mapping", "item_progression-flasks",
+	"unique_tiering", "skill_gem_tiering", csvMacroName, "veiled",
 }
 
 func (rg *RuleGenerator) handleMacroExpression(
 	macroExpressionNode *shared.ParseTree[symbols.LexingTokenType],
 	variables *map[string][]string,
-	sectionConditions []model2.Condition,
-) ([][]string, error) {
+	sectionConditions model2.ConditionExpression,
+) ([]CompiledRule, error) {
 	macroType := macroExpressionNode.Children[1].Token.ValueToString()
 	parameters := macroExpressionNode.FindAllSymbolNodes(symbols.ParseSymbolParameter.String())
 
@@ -175,12 +295,12 @@ func (rg *RuleGenerator) handleMacroExpression(
 		return rg.handleUniqueTiering(variables, parameters)
 	case "skill_gem_tiering":
 		return rg.handleGemTiering(variables, parameters)
-	case "handle_csv":
+	case csvMacroName:
 		return rg.handleCSVMacro(variables, parameters, sectionConditions)
 	case "veiled":
 		return rg.handleVeiledEquipment(variables, parameters)
 	default:
-		return nil, fmt.Errorf("unsupported macro type: %s", macroType)
+		return nil, diagnostics.Errorf(macroExpressionNode.Children[1].Position(), "unsupported macro type: %s", macroType)
 	}
 }
 
@@ -188,8 +308,8 @@ func (rg *RuleGenerator) handleMacroExpression(
 func (rg *RuleGenerator) handleVeiledEquipment(
 	variables *map[string][]string,
 	parameters []*shared.ParseTree[symbols.LexingTokenType],
-) ([][]string, error) {
-	var allGeneratedRules [][]string
+) ([]CompiledRule, error) {
+	var allGeneratedRules []CompiledRule
 
 	style, err := rg.extractStyle(parameters)
 
@@ -212,12 +332,16 @@ func (rg *RuleGenerator) handleVeiledEquipment(
 	rule := &model2.ParsedRule{
 		Style:          style,
 		Action:         model2.ShowRule,
-		Conditions:     []model2.Condition{identifiedCondition, modCondition},
+		Conditions:     model2.AllOf(identifiedCondition, modCondition),
 		Variables:      variables,
 		ValidBaseTypes: rg.validBaseTypes,
 	}
 
-	allGeneratedRules = append(allGeneratedRules, rg.compileParsedRule(rule, []model2.Condition{})...)
+	compiledRules, err := rg.compileParsedRule(rule, model2.ConditionExpression{})
+	if err != nil {
+		return nil, err
+	}
+	allGeneratedRules = append(allGeneratedRules, compiledRules...)
 
 	return allGeneratedRules, nil
 }
@@ -233,7 +357,7 @@ func (rg *RuleGenerator) extractStyle(parameters []*shared.ParseTree[symbols.Lex
 			continue
 		}
 
-		return nil, fmt.Errorf("unknown variable: " + parameterKey)
+		return nil, diagnostics.Errorf(childNode.Position(), "unknown variable: %s", parameterKey)
 	}
 
 	if styleString == "" {
@@ -253,18 +377,26 @@ func (rg *RuleGenerator) handleEquipmentProgression(
 	parameters []*shared.ParseTree[symbols.LexingTokenType],
 	minAreaLevel int,
 	maxAreaLevel int,
-) ([][]string, error) {
-	var allGeneratedRules [][]string
+) ([]CompiledRule, error) {
+	var allGeneratedRules []CompiledRule
 	itemsByCategory := make(map[string][]*model.ItemBase)
 	for i := range rg.weaponBases {
 		weapon := rg.weaponBases[i]
-		if rg.build.IsWeaponAssociated(weapon) {
+		associated, err := rg.build.IsWeaponAssociated(weapon)
+		if err != nil {
+			return nil, err
+		}
+		if associated {
 			itemsByCategory[weapon.Type] = append(itemsByCategory[weapon.Type], &rg.weaponBases[i])
 		}
 	}
 	for i := range rg.armorBases {
 		armor := rg.armorBases[i]
-		if rg.build.IsArmorAssociated(armor) {
+		associated, err := rg.build.IsArmorAssociated(armor, rg.log)
+		if err != nil {
+			return nil, err
+		}
+		if associated {
 			itemsByCategory[armor.Type] = append(itemsByCategory[armor.Type], &rg.armorBases[i])
 		}
 	}
@@ -276,12 +408,15 @@ func (rg *RuleGenerator) handleEquipmentProgression(
 		return allGeneratedRules, err
 	}
 
-	rg.produceProgression(
+	err = rg.produceProgression(
 		itemsByCategory, variables,
 		styleMap["$show_normal"], styleMap["$show_magic"], styleMap["$show_rare"],
 		styleMap["$hidden_normal"], styleMap["$hidden_magic"], styleMap["$hidden_rare"],
 		styleMap["$max_roll"],
 		&allGeneratedRules, false, minAreaLevel, maxAreaLevel)
+	if err != nil {
+		return nil, err
+	}
 
 	return allGeneratedRules, nil
 }
@@ -289,8 +424,8 @@ func (rg *RuleGenerator) handleEquipmentProgression(
 func (rg *RuleGenerator) handleFlaskProgression(
 	variables *map[string][]string,
 	parameters []*shared.ParseTree[symbols.LexingTokenType],
-) ([][]string, error) {
-	var allGeneratedRules [][]string
+) ([]CompiledRule, error) {
+	var allGeneratedRules []CompiledRule
 	itemsByCategory := make(map[string][]*model.ItemBase)
 	for i := range rg.flaskBases {
 		flask := rg.flaskBases[i]
@@ -307,7 +442,10 @@ func (rg *RuleGenerator) handleFlaskProgression(
 		return allGeneratedRules, err
 	}
 
-	rg.produceProgression(itemsByCategory, variables, shownStyle, shownStyle, shownStyle, hiddenStyle, hiddenStyle, hiddenStyle, nil, &allGeneratedRules, true, 0, 100)
+	err = rg.produceProgression(itemsByCategory, variables, shownStyle, shownStyle, shownStyle, hiddenStyle, hiddenStyle, hiddenStyle, nil, &allGeneratedRules, true, 0, 100)
+	if err != nil {
+		return nil, err
+	}
 
 	return allGeneratedRules, nil
 }
@@ -453,10 +591,10 @@ func (rg *RuleGenerator) produceProgression(
 	variables *map[string][]string,
 	shownNormal, shownMagic, shownRare,
 	hiddenNormal, hiddenMagic, hiddenRare, maxRoll *config.Style,
-	allGeneratedRules *[][]string,
+	allGeneratedRules *[]CompiledRule,
 	disableRare bool,
 	minAreaLevel, maxAreaLevel int,
-) {
+) error {
 	for _, categoryItems := range itemsByCategory {
 		if len(categoryItems) == 0 {
 			continue
@@ -466,43 +604,62 @@ func (rg *RuleGenerator) produceProgression(
 		for _, b := range buckets {
 			for _, item := range b.items {
 				if item.Armour != nil && maxRoll != nil {
-					rg.constructMaxRolledGearRule(variables, model2.ShowRule, *item, allGeneratedRules, maxRoll, fmt.Sprintf("%d", b.showEndLevel))
+					if err := rg.constructMaxRolledGearRule(variables, model2.ShowRule, *item, allGeneratedRules, maxRoll, fmt.Sprintf("%d", b.showEndLevel)); err != nil {
+						return err
+					}
 				}
 
-				rg.constructItemProgressionRule(variables, model2.ShowRule, *item, allGeneratedRules, shownNormal, fmt.Sprintf("%d", b.showEndLevel), "Normal")
-				rg.constructItemProgressionRule(variables, model2.ShowRule, *item, allGeneratedRules, shownMagic, fmt.Sprintf("%d", b.showEndLevel), "Magic")
+				if err := rg.constructItemProgressionRule(variables, model2.ShowRule, *item, allGeneratedRules, shownNormal, fmt.Sprintf("%d", b.showEndLevel), "Normal"); err != nil {
+					return err
+				}
+				if err := rg.constructItemProgressionRule(variables, model2.ShowRule, *item, allGeneratedRules, shownMagic, fmt.Sprintf("%d", b.showEndLevel), "Magic"); err != nil {
+					return err
+				}
 				if !disableRare {
-					rg.constructItemProgressionRule(variables, model2.ShowRule, *item, allGeneratedRules, shownRare, fmt.Sprintf("%d", b.showEndLevel), "Rare")
+					if err := rg.constructItemProgressionRule(variables, model2.ShowRule, *item, allGeneratedRules, shownRare, fmt.Sprintf("%d", b.showEndLevel), "Rare"); err != nil {
+						return err
+					}
 				}
 
 				if !b.isLastTier && b.hideStartLevel <= maxAreaLevel {
 					if item.Armour != nil && maxRoll != nil {
-						rg.constructMaxRolledGearRule(variables, model2.HideRule, *item, allGeneratedRules, maxRoll, fmt.Sprintf("%d", b.showEndLevel))
+						if err := rg.constructMaxRolledGearRule(variables, model2.HideRule, *item, allGeneratedRules, maxRoll, fmt.Sprintf("%d", b.showEndLevel)); err != nil {
+							return err
+						}
 					}
 
-					rg.constructItemProgressionRule(variables, model2.HideRule, *item, allGeneratedRules, hiddenNormal, fmt.Sprintf("%d", maxAreaLevel), "Normal")
-					rg.constructItemProgressionRule(variables, model2.HideRule, *item, allGeneratedRules, hiddenMagic, fmt.Sprintf("%d", maxAreaLevel), "Magic")
+					if err := rg.constructItemProgressionRule(variables, model2.HideRule, *item, allGeneratedRules, hiddenNormal, fmt.Sprintf("%d", maxAreaLevel), "Normal"); err != nil {
+						return err
+					}
+					if err := rg.constructItemProgressionRule(variables, model2.HideRule, *item, allGeneratedRules, hiddenMagic, fmt.Sprintf("%d", maxAreaLevel), "Magic"); err != nil {
+						return err
+					}
 					if !disableRare {
-						rg.constructItemProgressionRule(variables, model2.HideRule, *item, allGeneratedRules, hiddenRare, fmt.Sprintf("%d", maxAreaLevel), "Rare")
+						if err := rg.constructItemProgressionRule(variables, model2.HideRule, *item, allGeneratedRules, hiddenRare, fmt.Sprintf("%d", maxAreaLevel), "Rare"); err != nil {
+							return err
+						}
 					}
 				}
 			}
 		}
 
 		if len(outdated) > 0 {
-			rg.appendOutdatedHideRules(outdated, hiddenNormal, hiddenMagic, hiddenRare, variables, allGeneratedRules, minAreaLevel)
+			if err := rg.appendOutdatedHideRules(outdated, hiddenNormal, hiddenMagic, hiddenRare, variables, allGeneratedRules, minAreaLevel); err != nil {
+				return err
+			}
 		}
 	}
+	return nil
 }
 
 func (rg *RuleGenerator) constructMaxRolledGearRule(
 	variables *map[string][]string,
 	ruleType model2.RuleType,
 	item model.ItemBase,
-	allGeneratedRules *[][]string,
+	allGeneratedRules *[]CompiledRule,
 	maxRolledStyle *config.Style,
 	maxAreaLevel string,
-) {
+) error {
 	// Always-present conditions
 	areaCondition := model2.Condition{
 		Identifier: "@area_level",
@@ -552,24 +709,26 @@ func (rg *RuleGenerator) constructMaxRolledGearRule(
 	rule := &model2.ParsedRule{
 		Style:          maxRolledStyle,
 		Action:         ruleType,
-		Conditions:     conds,
+		Conditions:     model2.AllOf(conds...),
 		Variables:      variables,
 		ValidBaseTypes: rg.validBaseTypes,
 	}
 
-	*allGeneratedRules = append(
-		*allGeneratedRules,
-		rg.compileParsedRule(rule, []model2.Condition{})...,
-	)
+	compiledRules, err := rg.compileParsedRule(rule, model2.ConditionExpression{})
+	if err != nil {
+		return err
+	}
+	*allGeneratedRules = append(*allGeneratedRules, compiledRules...)
+	return nil
 }
 
 func (rg *RuleGenerator) appendOutdatedHideRules(
 	outdated []string,
 	hiddenNormal, hiddenMagic, hiddenRare *config.Style,
 	variables *map[string][]string,
-	allGeneratedRules *[][]string,
+	allGeneratedRules *[]CompiledRule,
 	minAreaLevel int,
-) {
+) error {
 	areaCond := model2.Condition{Identifier: "@area_level", Operator: ">=", Value: []string{fmt.Sprintf("%d", minAreaLevel)}}
 	typeCond := model2.Condition{Identifier: "@item_type", Operator: "==", Value: outdated}
 	for _, rc := range []struct {
@@ -584,22 +743,27 @@ func (rg *RuleGenerator) appendOutdatedHideRules(
 		pr := &model2.ParsedRule{
 			Style:          rc.style,
 			Action:         model2.HideRule,
-			Conditions:     []model2.Condition{areaCond, typeCond, cond},
+			Conditions:     model2.AllOf(areaCond, typeCond, cond),
 			Variables:      variables,
 			ValidBaseTypes: rg.validBaseTypes,
 		}
-		*allGeneratedRules = append(*allGeneratedRules, rg.compileParsedRule(pr, nil)...)
+		compiledRules, err := rg.compileParsedRule(pr, model2.ConditionExpression{})
+		if err != nil {
+			return err
+		}
+		*allGeneratedRules = append(*allGeneratedRules, compiledRules...)
 	}
+	return nil
 }
 
 func (rg *RuleGenerator) constructItemProgressionRule(
 	variables *map[string][]string,
 	ruleType model2.RuleType,
 	item model.ItemBase,
-	allGeneratedRules *[][]string,
+	allGeneratedRules *[]CompiledRule,
 	style *config.Style,
 	maxAreaLevel string,
-	rarity string) {
+	rarity string) error {
 	areaCondition := model2.Condition{
 		Identifier: "@area_level",
 		Operator:   "<=",
@@ -619,12 +783,17 @@ func (rg *RuleGenerator) constructItemProgressionRule(
 	rule := &model2.ParsedRule{
 		Style:          style,
 		Action:         ruleType,
-		Conditions:     []model2.Condition{areaCondition, baseTypeCondition, rarityCondition},
+		Conditions:     model2.AllOf(areaCondition, baseTypeCondition, rarityCondition),
 		Variables:      variables,
 		ValidBaseTypes: rg.validBaseTypes,
 	}
 
-	*allGeneratedRules = append(*allGeneratedRules, rg.compileParsedRule(rule, []model2.Condition{})...)
+	compiledRules, err := rg.compileParsedRule(rule, model2.ConditionExpression{})
+	if err != nil {
+		return err
+	}
+	*allGeneratedRules = append(*allGeneratedRules, compiledRules...)
+	return nil
 }
 
 type TieringConfig struct {
@@ -634,7 +803,7 @@ type TieringConfig struct {
 }
 
 // handleUniqueTiering generates tiered rules for unique items based on economy data.
-func (rg *RuleGenerator) handleUniqueTiering(variables *map[string][]string, parameters []*shared.ParseTree[symbols.LexingTokenType]) ([][]string, error) {
+func (rg *RuleGenerator) handleUniqueTiering(variables *map[string][]string, parameters []*shared.ParseTree[symbols.LexingTokenType]) ([]CompiledRule, error) {
 	uniqueConfig := TieringConfig{
 		InitialCondition: model2.Condition{
 			Identifier: "@rarity",
@@ -647,7 +816,7 @@ func (rg *RuleGenerator) handleUniqueTiering(variables *map[string][]string, par
 	return rg.generateTieredRules(variables, parameters, uniqueConfig)
 }
 
-func (rg *RuleGenerator) handleGemTiering(variables *map[string][]string, parameters []*shared.ParseTree[symbols.LexingTokenType]) ([][]string, error) {
+func (rg *RuleGenerator) handleGemTiering(variables *map[string][]string, parameters []*shared.ParseTree[symbols.LexingTokenType]) ([]CompiledRule, error) {
 	gemConfig := TieringConfig{
 		InitialCondition: model2.Condition{
 			Identifier: "@item_class",
@@ -664,8 +833,8 @@ func (rg *RuleGenerator) generateTieredRules(
 	variables *map[string][]string,
 	parameters []*shared.ParseTree[symbols.LexingTokenType],
 	tieringConfiguration TieringConfig,
-) ([][]string, error) {
-	generatedRules := make([][]string, 0)
+) ([]CompiledRule, error) {
+	generatedRules := make([]CompiledRule, 0)
 
 	// 1. Get tier styles from parameters (Common Logic)
 	tierStyles := make([]*config.Style, len(parameters))
@@ -673,7 +842,7 @@ func (rg *RuleGenerator) generateTieredRules(
 		_, value := rg.getKeyAndValueFromParameter(parameter)
 		style, err := rg.styleManager.GetStyle(value)
 		if err != nil {
-			panic(err)
+			return nil, diagnostics.Errorf(parameter.Position(), "%w", err)
 		}
 		tierStyles[i] = style
 	}
@@ -685,20 +854,25 @@ func (rg *RuleGenerator) generateTieredRules(
 		rule := &model2.ParsedRule{
 			Style:          style,
 			Action:         model2.ShowRule,
-			Conditions:     []model2.Condition{tieringConfiguration.InitialCondition},
+			Conditions:     model2.AllOf(tieringConfiguration.InitialCondition),
 			Variables:      variables,
 			ValidBaseTypes: rg.validBaseTypes,
 		}
 
-		generatedRules = append(generatedRules, rg.compileParsedRule(rule, []model2.Condition{})...)
+		compiledRules, err := rg.compileParsedRule(rule, model2.ConditionExpression{})
+		if err != nil {
+			return nil, err
+		}
+		generatedRules = append(generatedRules, compiledRules...)
 		return generatedRules, nil
 	}
 
 	// 3. Filter items from the economy cache based on the provided class (Customizable Logic)
 	itemsToCheck := make(map[string][]data_generation.EconomyCacheItem)
-	for league, items := range rg.economyCache {
+	// Leagues are visited in order, so the log output is the same on every run.
+	for _, league := range slices.Sorted(maps.Keys(rg.economyCache)) {
 		validItems := make([]data_generation.EconomyCacheItem, 0)
-		for _, item := range items {
+		for _, item := range rg.economyCache[league] {
 			if item.Class != tieringConfiguration.ItemClassToFilter {
 				continue
 			}
@@ -707,7 +881,7 @@ func (rg *RuleGenerator) generateTieredRules(
 			}
 			validItems = append(validItems, item)
 		}
-		log.Printf("Valid %s for League %s: %d\n", tieringConfiguration.ItemClassToFilter, league, len(validItems))
+		rg.log.Printf("Valid %s for League %s: %d\n", tieringConfiguration.ItemClassToFilter, league, len(validItems))
 		itemsToCheck[league] = validItems
 	}
 
@@ -731,6 +905,7 @@ func (rg *RuleGenerator) generateTieredRules(
 		ChaosOutlierPercentile:   0.95,
 		MinListingsForPercentile: 20,
 		ChasePotentialWeight:     rg.chasePotentialWeight,
+		Logger:                   rg.log,
 	})
 	if err != nil {
 		return generatedRules, err
@@ -761,12 +936,16 @@ func (rg *RuleGenerator) generateTieredRules(
 		rule := &model2.ParsedRule{
 			Style:          style,
 			Action:         model2.ShowRule,
-			Conditions:     []model2.Condition{tieringConfiguration.InitialCondition, tieredItemsCondition},
+			Conditions:     model2.AllOf(tieringConfiguration.InitialCondition, tieredItemsCondition),
 			Variables:      variables,
 			ValidBaseTypes: rg.validBaseTypes,
 		}
 
-		generatedRules = append(generatedRules, rg.compileParsedRule(rule, []model2.Condition{})...)
+		compiledRules, err := rg.compileParsedRule(rule, model2.ConditionExpression{})
+		if err != nil {
+			return nil, err
+		}
+		generatedRules = append(generatedRules, compiledRules...)
 	}
 
 	return generatedRules, nil
@@ -847,8 +1026,8 @@ func groupByProperties(entries []config.BaseTypeAutomationEntry, styleManager *S
 }
 
 //goland:noinspection t
-func (rg *RuleGenerator) handleCSVMacro(variables *map[string][]string, parameters []*shared.ParseTree[symbols.LexingTokenType], sectionConditions []model2.Condition) ([][]string, error) {
-	allGeneratedRules := make([][]string, 0)
+func (rg *RuleGenerator) handleCSVMacro(variables *map[string][]string, parameters []*shared.ParseTree[symbols.LexingTokenType], sectionConditions model2.ConditionExpression) ([]CompiledRule, error) {
+	allGeneratedRules := make([]CompiledRule, 0)
 
 	category := ""
 
@@ -859,7 +1038,7 @@ func (rg *RuleGenerator) handleCSVMacro(variables *map[string][]string, paramete
 		case "category":
 			category = value
 		default:
-			return allGeneratedRules, fmt.Errorf("unsupported parameter: " + key)
+			return allGeneratedRules, diagnostics.Errorf(parameter.Position(), "unsupported parameter: %s", key)
 		}
 	}
 
@@ -920,181 +1099,178 @@ func (rg *RuleGenerator) handleCSVMacro(variables *map[string][]string, paramete
 		rule := &model2.ParsedRule{
 			Style:          &style,
 			Action:         ruleAction,
-			Conditions:     conditions,
+			Conditions:     model2.AllOf(conditions...),
 			Variables:      variables,
 			ValidBaseTypes: rg.validBaseTypes,
 		}
 
-		allGeneratedRules = append(allGeneratedRules, rg.compileParsedRule(rule, sectionConditions)...)
+		compiledRules, err := rg.compileParsedRule(rule, sectionConditions)
+		if err != nil {
+			return allGeneratedRules, err
+		}
+		allGeneratedRules = append(allGeneratedRules, compiledRules...)
 	}
 
 	return allGeneratedRules, nil
 }
 
-var conditionOrder = map[string]int{
-	// Booleans
-	"@quality":    1,
-	"@corrupted":  2,
-	"@fractured":  3,
-	"@identified": 4,
-
-	// Numeric
-	"@base_armour":        5,
-	"@base_evasion":       6,
-	"@base_energy_shield": 7,
-	"@base_ward":          8,
-	"@stack_size":         9,
-	"@height":             10,
-	"@width":              11,
-	"@area_level":         12,
-	"@map_tier":           13,
-
-	// Hash Set
-	"@sockets":      14,
-	"@socket_group": 15,
-
-	// Arrays
-	"@item_class": 16,
-	"@item_type":  17,
-	"@rarity":     18,
-
-	// Mods
-	"@has_explicit_mod": 19,
+// sortConditions orders conditions by the weights of their identifiers, so blocks always list them the same way.
+func sortConditions(toSort []model2.Condition) {
+	sort.Slice(toSort, func(i, j int) bool {
+		return conditionOrder(toSort[i].Identifier) < conditionOrder(toSort[j].Identifier)
+	})
 }
 
-//goland:noinspection t
-func (rg *RuleGenerator) compileParsedRule(rule *model2.ParsedRule, sectionConditions []model2.Condition) [][]string {
-	var macroConditions []model2.Condition
-	var standardRuleConditions []model2.Condition
-	macros := []string{"@class_use"}
+func conditionOrder(identifier string) int {
+	registered, ok := conditions.Lookup(identifier)
+	if !ok {
+		return 999
+	}
+	return registered.Order
+}
 
-	for _, cond := range rule.Conditions {
-		if slices.Contains(macros, cond.Identifier) {
-			macroConditions = append(macroConditions, cond)
-		} else {
-			standardRuleConditions = append(standardRuleConditions, cond)
+func isMacroCondition(identifier string) bool {
+	registered, ok := conditions.Lookup(identifier)
+	return ok && registered.Macro
+}
+
+// compileParsedRule compiles a rule into filter blocks.
+// The section's and the rule's conditions are expanded into disjunctive normal form, and each alternative that
+// remains after merging becomes a block of its own.
+func (rg *RuleGenerator) compileParsedRule(rule *model2.ParsedRule, sectionConditions model2.ConditionExpression) ([]CompiledRule, error) {
+	var allGeneratedRules []CompiledRule
+	for _, conditions := range combineAlternatives(sectionConditions, rule.Conditions) {
+		generated, err := rg.compileConjunction(rule, conditions)
+		if err != nil {
+			return nil, err
 		}
+		allGeneratedRules = append(allGeneratedRules, generated...)
 	}
+	return allGeneratedRules, nil
+}
 
-	ruleConditionsMap := make(map[string]model2.Condition, len(standardRuleConditions))
-	for _, ruleCond := range standardRuleConditions {
-		key := ruleCond.Identifier + ":" + ruleCond.Operator
-		ruleConditionsMap[key] = ruleCond
-	}
+// compileConjunction compiles a single alternative of a rule, whose conditions must all hold.
+//
+//goland:noinspection t
+func (rg *RuleGenerator) compileConjunction(rule *model2.ParsedRule, conditions []model2.Condition) ([]CompiledRule, error) {
+	var macroConditions []model2.Condition
+	var finalStandardConditions []model2.Condition
 
-	finalStandardConditions := make([]model2.Condition, 0, len(standardRuleConditions)+len(sectionConditions))
-	for _, sectionCond := range sectionConditions {
-		key := sectionCond.Identifier + ":" + sectionCond.Operator
-		if _, isOverridden := ruleConditionsMap[key]; !isOverridden {
-			finalStandardConditions = append(finalStandardConditions, sectionCond)
+	for _, cond := range conditions {
+		if isMacroCondition(cond.Identifier) {
+			macroConditions = append(macroConditions, cond)
+		} else {
+			finalStandardConditions = append(finalStandardConditions, cond)
 		}
 	}
-	finalStandardConditions = append(finalStandardConditions, standardRuleConditions...)
 
 	if len(macroConditions) == 0 {
-		sort.Slice(finalStandardConditions, func(i, j int) bool {
-			orderI, okI := conditionOrder[finalStandardConditions[i].Identifier]
-			if !okI {
-				orderI = 999
-			}
-			orderJ, okJ := conditionOrder[finalStandardConditions[j].Identifier]
-			if !okJ {
-				orderJ = 999
-			}
-			return orderI < orderJ
-		})
+		sortConditions(finalStandardConditions)
 
 		compiledFinalConditions := make([]string, len(finalStandardConditions))
 		for i, cond := range finalStandardConditions {
-			compiledFinalConditions[i] = cond.ConstructCompiledCondition(rule.Variables, rule.ValidBaseTypes)
+			compiled, err := cond.ConstructCompiledCondition(rule.Variables, rule.ValidBaseTypes, rg.warnings)
+			if err != nil {
+				return nil, err
+			}
+			compiledFinalConditions[i] = compiled
 		}
 
 		if rule.Style == nil {
-			panic(fmt.Errorf("rule style is nil, rule: %v", rule.Conditions))
+			return nil, fmt.Errorf("rule style is nil, rule: %v", conditions)
 		}
 
 		finalRule := rg.ruleFactory.ConstructRule(rule.Action, *rule.Style, compiledFinalConditions)
-		return [][]string{finalRule}
+		return []CompiledRule{finalRule}, nil
 	}
 
-	var allGeneratedRules [][]string
+	var allGeneratedRules []CompiledRule
 	for _, macro := range macroConditions {
-		var generatedForMacro [][]string
+		var generatedForMacro []CompiledRule
+		var err error
 		switch macro.Identifier {
 		case "@class_use":
-			generatedForMacro = rg.handleClassUseMacro(rule.Action, rule.Style, finalStandardConditions, macro, rule.Variables)
+			generatedForMacro, err = rg.handleClassUseMacro(rule.Action, rule.Style, finalStandardConditions, macro, rule.Variables)
 		default:
-			panic("unknown macro: " + macro.Identifier)
+			err = diagnostics.Errorf(macro.Position, "unknown macro: %s", macro.Identifier)
+		}
+		if err != nil {
+			return nil, err
 		}
 		allGeneratedRules = append(allGeneratedRules, generatedForMacro...)
 	}
-	return allGeneratedRules
+	return allGeneratedRules, nil
 }
 
 //goland:noinspection t
 func (rg *RuleGenerator) handleClassUseMacro(
 	action model2.RuleType, style *config.Style, baseConditions []model2.Condition,
 	macro model2.Condition, variables *map[string][]string,
-) [][]string {
-	generateRule := func(newCond model2.Condition) []string {
+) ([]CompiledRule, error) {
+	if style == nil {
+		return nil, diagnostics.Errorf(macro.Position, "rule style is nil, rule: %v", baseConditions)
+	}
+
+	generateRule := func(newCond model2.Condition) (CompiledRule, error) {
 		finalConditions := make([]model2.Condition, 0, len(baseConditions)+1)
 		finalConditions = append(finalConditions, baseConditions...)
 		finalConditions = append(finalConditions, newCond)
 
-		sort.Slice(finalConditions, func(i, j int) bool {
-			orderI, okI := conditionOrder[finalConditions[i].Identifier]
-			if !okI {
-				orderI = 999
-			}
-			orderJ, okJ := conditionOrder[finalConditions[j].Identifier]
-			if !okJ {
-				orderJ = 999
-			}
-			return orderI < orderJ
-		})
+		sortConditions(finalConditions)
 
 		compiledConditions := make([]string, len(finalConditions))
 		for i, cond := range finalConditions {
-			compiledConditions[i] = cond.ConstructCompiledCondition(variables, rg.validBaseTypes)
+			compiled, err := cond.ConstructCompiledCondition(variables, rg.validBaseTypes, rg.warnings)
+			if err != nil {
+				return CompiledRule{}, err
+			}
+			compiledConditions[i] = compiled
 		}
-		return rg.ruleFactory.ConstructRule(action, *style, compiledConditions)
+		return rg.ruleFactory.ConstructRule(action, *style, compiledConditions), nil
 	}
 
 	var weaponClasses []string
-	var armorClasses []string
-	switch macro.Value[0] {
+	var wantAssociated bool
+	switch strings.ToLower(macro.Value[0]) {
 	case "true":
 		weaponClasses = rg.build.AssociatedWeaponClasses()
-		for _, item := range rg.armorBases {
-			if rg.build.IsArmorAssociated(item) {
-				armorClasses = append(armorClasses, item.GetBaseType())
-			}
-		}
+		wantAssociated = true
 	case "false":
 		weaponClasses = rg.build.UnassociatedWeaponClasses()
-		for _, item := range rg.armorBases {
-			if !rg.build.IsArmorAssociated(item) {
-				armorClasses = append(armorClasses, item.GetBaseType())
-			}
-		}
 	default:
-		panic(fmt.Sprintf("invalid value for @class_use: %s", macro.Value[0]))
+		return nil, diagnostics.Errorf(macro.Position, "invalid value for @class_use: %s", macro.Value[0])
+	}
+
+	var armorClasses []string
+	for _, item := range rg.armorBases {
+		associated, err := rg.build.IsArmorAssociated(item, rg.log)
+		if err != nil {
+			return nil, diagnostics.Errorf(macro.Position, "%w", err)
+		}
+		if associated == wantAssociated {
+			armorClasses = append(armorClasses, item.GetBaseType())
+		}
 	}
 
-	weaponryCond := model2.Condition{Identifier: "@item_class", Operator: macro.Operator, Value: weaponClasses}
-	weaponryRule := generateRule(weaponryCond)
+	weaponryCond := model2.Condition{Identifier: "@item_class", Operator: macro.Operator, Value: weaponClasses, Position: macro.Position}
+	weaponryRule, err := generateRule(weaponryCond)
+	if err != nil {
+		return nil, err
+	}
 
-	armorCond := model2.Condition{Identifier: "@item_type", Operator: macro.Operator, Value: armorClasses}
-	armorRule := generateRule(armorCond)
+	armorCond := model2.Condition{Identifier: "@item_type", Operator: macro.Operator, Value: armorClasses, Position: macro.Position}
+	armorRule, err := generateRule(armorCond)
+	if err != nil {
+		return nil, err
+	}
 
-	return [][]string{weaponryRule, armorRule}
+	return []CompiledRule{weaponryRule, armorRule}, nil
 }
 
-// getDropLevel helper remains the same.
+// getDropLevel returns the drop level of the item, or 0 if it has none. Missing drop levels are reported by prepareItemData.
 func getDropLevel(item *model.ItemBase) int {
 	if item.DropLevel != nil {
 		return *item.DropLevel
 	}
-	log.Printf("WARNING: Item '%s' has a nil DropLevel; defaulting to level 0.", item.Name)
 	return 0
 }
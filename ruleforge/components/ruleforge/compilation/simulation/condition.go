@@ -0,0 +1,263 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var rarityOrder = []string{"Normal", "Magic", "Rare", "Unique"}
+
+// numericProperties maps the numeric filter conditions to the item property they test.
+var numericProperties = map[string]func(Item) int{
+	"AreaLevel":                func(i Item) int { return i.AreaLevel },
+	"ItemLevel":                func(i Item) int { return i.ItemLevel },
+	"DropLevel":                func(i Item) int { return i.DropLevel },
+	"StackSize":                func(i Item) int { return i.StackSize },
+	"Quality":                  func(i Item) int { return i.Quality },
+	"MapTier":                  func(i Item) int { return i.MapTier },
+	"GemLevel":                 func(i Item) int { return i.GemLevel },
+	"Width":                    func(i Item) int { return i.Width },
+	"Height":                   func(i Item) int { return i.Height },
+	"LinkedSockets":            func(i Item) int { return largestGroup(i.Sockets) },
+	"BaseArmour":               func(i Item) int { return i.BaseArmour },
+	"BaseEvasion":              func(i Item) int { return i.BaseEvasion },
+	"BaseEnergyShield":         func(i Item) int { return i.BaseEnergyShield },
+	"BaseWard":                 func(i Item) int { return i.BaseWard },
+	"MemoryStrands":            func(i Item) int { return i.MemoryStrands },
+	"HasSearingExarchImplicit": func(i Item) int { return i.SearingExarchTier },
+	"HasEaterOfWorldsImplicit": func(i Item) int { return i.EaterOfWorldsTier },
+}
+
+// booleanProperties maps the boolean filter conditions to the item property they test.
+var booleanProperties = map[string]func(Item) bool{
+	"Corrupted":        func(i Item) bool { return i.Corrupted },
+	"Identified":       func(i Item) bool { return !i.Unidentified },
+	"Mirrored":         func(i Item) bool { return i.Mirrored },
+	"FracturedItem":    func(i Item) bool { return i.Fractured },
+	"SynthesisedItem":  func(i Item) bool { return i.Synthesised },
+	"Replica":          func(i Item) bool { return i.Replica },
+	"Scourged":         func(i Item) bool { return i.Scourged },
+	"TransfiguredGem":  func(i Item) bool { return i.TransfiguredGem },
+	"BlightedMap":      func(i Item) bool { return i.BlightedMap },
+	"UberBlightedMap":  func(i Item) bool { return i.UberBlightedMap },
+	"ZanaMemory":       func(i Item) bool { return i.ZanaMemory },
+	"AlternateQuality": func(i Item) bool { return i.AlternateQuality },
+	"AnyEnchantment":   func(i Item) bool { return len(i.Enchantments) > 0 },
+	"ShaperItem":       func(i Item) bool { return slices.Contains(i.Influences, "Shaper") },
+	"ElderItem":        func(i Item) bool { return slices.Contains(i.Influences, "Elder") },
+	"HasImplicitMod": func(i Item) bool {
+		return len(i.ImplicitMods) > 0 || i.SearingExarchTier > 0 || i.EaterOfWorldsTier > 0
+	},
+}
+
+// modProperties maps the mod filter conditions to the mods they search.
+var modProperties = map[string]func(Item) []string{
+	"HasExplicitMod":         func(i Item) []string { return i.ExplicitMods },
+	"HasEnchantment":         func(i Item) []string { return i.Enchantments },
+	"EnchantmentPassiveNode": func(i Item) []string { return i.Enchantments },
+}
+
+// Matches reports whether the item meets the condition.
+// Conditions the simulator does not know never match and return an error describing them.
+func (c Condition) Matches(item Item) (bool, error) {
+	if property, ok := numericProperties[c.Keyword]; ok {
+		return c.matchesNumber(property(item))
+	}
+	if property, ok := booleanProperties[c.Keyword]; ok {
+		return c.matchesBoolean(property(item))
+	}
+	if property, ok := modProperties[c.Keyword]; ok {
+		return c.matchesMods(property(item))
+	}
+
+	switch c.Keyword {
+	case "Class":
+		return c.matchesText(item.Class), nil
+	case "BaseType":
+		return c.matchesText(item.BaseType), nil
+	case "Rarity":
+		return c.matchesRarity(item.Rarity)
+	case "Sockets":
+		return c.matchesSockets([]string{strings.Join(socketGroups(item.Sockets), "")})
+	case "SocketGroup":
+		return c.matchesSockets(socketGroups(item.Sockets))
+	case "HasInfluence":
+		return c.matchesInfluence(item.Influences), nil
+	}
+	return false, fmt.Errorf("line %d: the simulator does not support %s; treating it as not matching", c.Line, c.Keyword)
+}
+
+// matchesNumber matches if any value compares true; a negated condition matches if the number equals none of them.
+func (c Condition) matchesNumber(actual int) (bool, error) {
+	found := false
+	for _, value := range c.Values {
+		expected, err := strconv.Atoi(value)
+		if err != nil {
+			return false, fmt.Errorf("line %d: %s expects numbers, got %q", c.Line, c.Keyword, value)
+		}
+		if negates(c.Operator) {
+			found = found || actual == expected
+		} else if compare(c.Operator, actual, expected) {
+			return true, nil
+		}
+	}
+	return negates(c.Operator) && !found, nil
+}
+
+func (c Condition) matchesBoolean(actual bool) (bool, error) {
+	if len(c.Values) != 1 {
+		return false, fmt.Errorf("line %d: %s expects a single True or False", c.Line, c.Keyword)
+	}
+	expected, err := strconv.ParseBool(c.Values[0])
+	if err != nil {
+		return false, fmt.Errorf("line %d: %s expects True or False, got %q", c.Line, c.Keyword, c.Values[0])
+	}
+	return negates(c.Operator) != (actual == expected), nil
+}
+
+// matchesText matches Class and BaseType: `==` requires an exact match, otherwise any value may be part of the text.
+func (c Condition) matchesText(actual string) bool {
+	found := slices.ContainsFunc(c.Values, func(value string) bool {
+		if c.Operator == "==" {
+			return actual == value
+		}
+		return strings.Contains(strings.ToLower(actual), strings.ToLower(value))
+	})
+	return negates(c.Operator) != found
+}
+
+// matchesMods counts the mods that match any of the values and compares that count, at least one by default.
+func (c Condition) matchesMods(mods []string) (bool, error) {
+	count := 0
+	for _, mod := range mods {
+		if slices.ContainsFunc(c.Values, func(value string) bool {
+			if c.Operator == "==" {
+				return mod == value
+			}
+			return strings.Contains(strings.ToLower(mod), strings.ToLower(value))
+		}) {
+			count++
+		}
+	}
+
+	if c.Count == nil {
+		return negates(c.Operator) != (count > 0), nil
+	}
+	return compare(c.Operator, count, *c.Count), nil
+}
+
+func (c Condition) matchesRarity(actual string) (bool, error) {
+	actualRank := slices.Index(rarityOrder, actual)
+	if actualRank == -1 {
+		return false, fmt.Errorf("line %d: unknown item rarity %q", c.Line, actual)
+	}
+
+	for _, value := range c.Values {
+		expectedRank := slices.Index(rarityOrder, value)
+		if expectedRank == -1 {
+			return false, fmt.Errorf("line %d: unknown rarity %q", c.Line, value)
+		}
+		if compare(c.Operator, actualRank, expectedRank) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchesInfluence matches if the item has any of the influences, or with `==` all of them, as PoE does.
+func (c Condition) matchesInfluence(influences []string) bool {
+	has := func(value string) bool {
+		if value == "None" {
+			return len(influences) == 0
+		}
+		return slices.Contains(influences, value)
+	}
+
+	if c.Operator == "==" {
+		return !slices.ContainsFunc(c.Values, func(value string) bool { return !has(value) })
+	}
+	return negates(c.Operator) != slices.ContainsFunc(c.Values, has)
+}
+
+// matchesSockets matches socket specs such as "5", "RGB" or "6W" against the given socket groups.
+// The number is compared with the operator; the letters must be present, exactly that many times with `==`.
+func (c Condition) matchesSockets(groups []string) (bool, error) {
+	for _, value := range c.Values {
+		spec := strings.ToUpper(value)
+		digits := strings.IndexFunc(spec, func(r rune) bool { return r < '0' || r > '9' })
+		if digits == -1 {
+			digits = len(spec)
+		}
+
+		wanted := -1
+		if digits > 0 {
+			wanted, _ = strconv.Atoi(spec[:digits])
+		}
+		colors := spec[digits:]
+
+		for _, group := range groups {
+			if wanted != -1 && !compare(c.Operator, len(group), wanted) {
+				continue
+			}
+			if socketColorsMatch(group, colors, c.Operator == "==") {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func socketColorsMatch(group, colors string, exact bool) bool {
+	for _, color := range "RGBWAD" {
+		wanted := strings.Count(colors, string(color))
+		have := strings.Count(group, string(color))
+		if have < wanted || (exact && wanted > 0 && have != wanted) {
+			return false
+		}
+	}
+	return true
+}
+
+// socketGroups splits "R-G B" into its linked groups, "RG" and "B".
+func socketGroups(sockets string) []string {
+	var groups []string
+	for _, group := range strings.Fields(sockets) {
+		groups = append(groups, strings.ReplaceAll(group, "-", ""))
+	}
+	if len(groups) == 0 {
+		groups = []string{""}
+	}
+	return groups
+}
+
+func largestGroup(sockets string) int {
+	largest := 0
+	for _, group := range socketGroups(sockets) {
+		largest = max(largest, len(group))
+	}
+	return largest
+}
+
+// compare applies a filter operator; no operator and `=` test for equality.
+func compare(operator string, actual, expected int) bool {
+	switch operator {
+	case "<":
+		return actual < expected
+	case "<=":
+		return actual <= expected
+	case ">":
+		return actual > expected
+	case ">=":
+		return actual >= expected
+	case "!", "!=":
+		return actual != expected
+	default:
+		return actual == expected
+	}
+}
+
+func negates(operator string) bool {
+	return operator == "!" || operator == "!="
+}
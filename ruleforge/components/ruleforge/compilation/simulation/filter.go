@@ -0,0 +1,190 @@
+package simulation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// actionKeywords are the block lines that style an item instead of selecting it.
+var actionKeywords = map[string]bool{
+	"SetTextColor":                 true,
+	"SetBorderColor":               true,
+	"SetBackgroundColor":           true,
+	"SetFontSize":                  true,
+	"PlayAlertSound":               true,
+	"PlayAlertSoundPositional":     true,
+	"CustomAlertSound":             true,
+	"CustomAlertSoundOptional":     true,
+	"DisableDropSound":             true,
+	"EnableDropSound":              true,
+	"DisableDropSoundIfAlertSound": true,
+	"EnableDropSoundIfAlertSound":  true,
+	"MinimapIcon":                  true,
+	"PlayEffect":                   true,
+}
+
+// Block is a Show, Hide or Minimal block of a filter.
+type Block struct {
+	// Line is the 1-based line of the block's Show/Hide keyword.
+	Line int
+	// Visibility is "Show", "Hide" or "Minimal".
+	Visibility string
+	// Continue is set when matching items carry on to the next blocks.
+	Continue   bool
+	Conditions []Condition
+	// Actions are the styling lines of the block, trimmed.
+	Actions []string
+	// Lines are the lines of the block as they appear in the filter, without empty and comment lines.
+	Lines []string
+}
+
+// Condition is a single condition line of a block, e.g. `BaseType == "Mirror of Kalandra"`.
+type Condition struct {
+	Line     int
+	Keyword  string
+	Operator string
+	// Count is the number of matches required by counted conditions like `HasExplicitMod >=2 "a" "b"`; nil when not given.
+	Count  *int
+	Values []string
+}
+
+func (c Condition) String() string {
+	parts := []string{c.Keyword}
+	if c.Operator != "" || c.Count != nil {
+		operator := c.Operator
+		if c.Count != nil {
+			operator += strconv.Itoa(*c.Count)
+		}
+		parts = append(parts, operator)
+	}
+	for _, value := range c.Values {
+		parts = append(parts, strconv.Quote(value))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseFilter splits the lines of a filter into its blocks. Comments and lines outside blocks are ignored.
+func ParseFilter(lines []string) ([]Block, error) {
+	var blocks []Block
+	var current *Block
+
+	for i, raw := range lines {
+		lineNumber := i + 1
+		tokens, err := tokenize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+
+		keyword := tokens[0]
+		switch keyword {
+		case "Show", "Hide", "Minimal":
+			blocks = append(blocks, Block{Line: lineNumber, Visibility: keyword, Lines: []string{raw}})
+			current = &blocks[len(blocks)-1]
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: %q is outside of a block", lineNumber, keyword)
+		}
+		current.Lines = append(current.Lines, raw)
+
+		switch {
+		case keyword == "Continue":
+			current.Continue = true
+		case actionKeywords[keyword]:
+			current.Actions = append(current.Actions, strings.TrimSpace(raw))
+		default:
+			current.Conditions = append(current.Conditions, parseCondition(lineNumber, tokens))
+		}
+	}
+	return blocks, nil
+}
+
+func parseCondition(line int, tokens []string) Condition {
+	condition := Condition{Line: line, Keyword: tokens[0]}
+	values := tokens[1:]
+
+	if len(values) > 0 {
+		if operator, count, ok := splitOperator(values[0]); ok {
+			condition.Operator = operator
+			condition.Count = count
+			values = values[1:]
+		}
+	}
+	condition.Values = values
+	return condition
+}
+
+// splitOperator recognizes an operator token, optionally followed by a count as in `>=2`.
+func splitOperator(token string) (string, *int, bool) {
+	end := strings.IndexFunc(token, func(r rune) bool { return !strings.ContainsRune("=!<>", r) })
+	if end == -1 {
+		end = len(token)
+	}
+
+	operator := token[:end]
+	switch operator {
+	case "=", "==", "!", "!=", "<", "<=", ">", ">=":
+	default:
+		return "", nil, false
+	}
+
+	if end == len(token) {
+		return operator, nil, true
+	}
+	count, err := strconv.Atoi(token[end:])
+	if err != nil {
+		return "", nil, false
+	}
+	return operator, &count, true
+}
+
+// tokenize splits a filter line into words and quoted strings, dropping comments.
+func tokenize(line string) ([]string, error) {
+	var tokens []string
+	runes := []rune(line)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '#':
+			return tokens, nil
+		case r == '"':
+			var value strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					value.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			tokens = append(tokens, value.String())
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '"' && runes[i] != '#' {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		}
+	}
+	return tokens, nil
+}
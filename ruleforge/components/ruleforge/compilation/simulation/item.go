@@ -0,0 +1,360 @@
+package simulation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Item describes a dropped item. Fields are named after the filter conditions that test them.
+type Item struct {
+	Class    string
+	BaseType string
+	// Name is the item's own name, e.g. of a rare or unique; informational only.
+	Name   string
+	Rarity string
+
+	AreaLevel        int
+	ItemLevel        int
+	DropLevel        int
+	StackSize        int
+	Quality          int
+	MapTier          int
+	GemLevel         int
+	Width            int
+	Height           int
+	BaseArmour       int
+	BaseEvasion      int
+	BaseEnergyShield int
+	BaseWard         int
+	MemoryStrands    int
+
+	// Sockets uses the notation of the game, e.g. "R-G-B B": linked sockets are joined by '-', groups are separated by spaces.
+	Sockets string
+
+	Corrupted        bool
+	Unidentified     bool
+	Mirrored         bool
+	Fractured        bool
+	Synthesised      bool
+	Replica          bool
+	Scourged         bool
+	TransfiguredGem  bool
+	BlightedMap      bool
+	UberBlightedMap  bool
+	ZanaMemory       bool
+	AlternateQuality bool
+
+	// Influences lists the influences by name, e.g. "Shaper" or "Warlord".
+	Influences []string
+
+	// ExplicitMods and ImplicitMods hold mod names, e.g. "Tyrannical". HasExplicitMod matches the former,
+	// HasImplicitMod only whether the item has any implicit.
+	ExplicitMods []string
+	ImplicitMods []string
+	Enchantments []string
+
+	// SearingExarchTier and EaterOfWorldsTier are the tiers of the eldritch implicits, 0 for none.
+	SearingExarchTier int
+	EaterOfWorldsTier int
+}
+
+// ParseItem reads an item given as JSON or as the text the game copies to the clipboard.
+func ParseItem(data []byte) (Item, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return ParseItemJSON(trimmed)
+	}
+	return ParseClipboardItem(string(data))
+}
+
+// ParseItemJSON reads an item from a JSON object with the fields of Item. Unknown fields are an error.
+func ParseItemJSON(data []byte) (Item, error) {
+	var item Item
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&item); err != nil {
+		return Item{}, fmt.Errorf("parsing item JSON: %w", err)
+	}
+	return item, nil
+}
+
+const clipboardSeparator = "--------"
+
+var (
+	// advancedModHeader matches the mod headers of the advanced item description (Ctrl+Alt+C),
+	// e.g. `{ Prefix Modifier "Tyrannical" (Tier: 1) — Damage, Physical, Attack }`.
+	// The tier name of eldritch implicits follows the keyword, e.g. `{ Searing Exarch Implicit Modifier (Greater) — Damage }`.
+	advancedModHeader = regexp.MustCompile(`^\{ (.*?)Modifier(?: "([^"]+)")?(?: \((\w+)\))?.*\}$`)
+	modSuffix         = regexp.MustCompile(` \((implicit|enchant|crafted|fractured|scourge)\)$`)
+	leadingNumber     = regexp.MustCompile(`^[+]?([\d,]+)`)
+)
+
+var influenceLines = map[string]string{
+	"Shaper Item":   "Shaper",
+	"Elder Item":    "Elder",
+	"Crusader Item": "Crusader",
+	"Hunter Item":   "Hunter",
+	"Redeemer Item": "Redeemer",
+	"Warlord Item":  "Warlord",
+}
+
+// ParseClipboardItem reads an item from the text the game copies with Ctrl+C or Ctrl+Alt+C.
+// The advanced description (Ctrl+Alt+C) is preferred, as only it contains the mod names that HasExplicitMod matches;
+// for the plain description the mod lines themselves are used.
+// The area level is not part of the description and has to be set separately.
+func ParseClipboardItem(text string) (Item, error) {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	sections := splitSections(text)
+	if len(sections) == 0 || len(sections[0]) < 2 {
+		return Item{}, fmt.Errorf("item text must start with the item class, rarity and name")
+	}
+
+	var item Item
+	if err := parseClipboardHeader(sections[0], &item); err != nil {
+		return Item{}, err
+	}
+
+	afterItemLevel := false
+	for _, section := range sections[1:] {
+		if strings.HasPrefix(section[0], "Requirements") || strings.HasPrefix(section[0], "Requires ") {
+			continue
+		}
+
+		sectionHasItemLevel := false
+		// header is the advanced mod header the following lines belong to, if any.
+		var header *modHeader
+		for _, line := range section {
+			if key, value, ok := strings.Cut(line, ": "); ok && applyClipboardProperty(&item, key, value) {
+				sectionHasItemLevel = sectionHasItemLevel || key == "Item Level"
+				continue
+			}
+			if applyClipboardFlag(&item, line) {
+				continue
+			}
+
+			if match := advancedModHeader.FindStringSubmatch(line); match != nil {
+				header = applyAdvancedModHeader(&item, strings.TrimSpace(match[1]), match[2], match[3])
+				continue
+			}
+
+			mod, suffix := line, ""
+			if match := modSuffix.FindStringSubmatch(line); match != nil {
+				mod, suffix = strings.TrimSuffix(line, match[0]), match[1]
+			}
+
+			switch {
+			case header != nil:
+				// Named mods were recorded by their header; the lines of unnamed ones stand in for the name.
+				if header.mods != nil && !header.named {
+					*header.mods = append(*header.mods, mod)
+				}
+			case suffix != "":
+				applySuffixedMod(&item, mod, suffix)
+			case afterItemLevel:
+				// Unmarked lines after the item level are explicit mods.
+				item.ExplicitMods = append(item.ExplicitMods, mod)
+			}
+		}
+		afterItemLevel = afterItemLevel || sectionHasItemLevel
+	}
+	return item, nil
+}
+
+func splitSections(text string) [][]string {
+	var sections [][]string
+	for _, chunk := range strings.Split(text, clipboardSeparator) {
+		var lines []string
+		for _, line := range strings.Split(chunk, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) > 0 {
+			sections = append(sections, lines)
+		}
+	}
+	return sections
+}
+
+func parseClipboardHeader(header []string, item *Item) error {
+	var names []string
+	for _, line := range header {
+		key, value, ok := strings.Cut(line, ": ")
+		switch {
+		case ok && key == "Item Class":
+			item.Class = value
+		case ok && key == "Rarity":
+			// Currency, gems and cards show their kind as rarity, but filters treat them as normal items.
+			item.Rarity = value
+			if !slices.Contains(rarityOrder, value) {
+				item.Rarity = "Normal"
+			}
+		default:
+			names = append(names, line)
+		}
+	}
+
+	if item.Class == "" || item.Rarity == "" || len(names) == 0 {
+		return fmt.Errorf("item text must start with \"Item Class:\", \"Rarity:\" and the item's name")
+	}
+
+	// Rares and uniques show their name above the base type; other items only show the base type.
+	item.BaseType = names[len(names)-1]
+	if len(names) > 1 {
+		item.Name = names[0]
+	}
+
+	if item.Rarity == "Unique" && strings.HasPrefix(item.Name, "Replica ") {
+		item.Replica = true
+	}
+	for prefix, flag := range map[string]*bool{
+		"Synthesised ":    &item.Synthesised,
+		"Blighted ":       &item.BlightedMap,
+		"Blight-ravaged ": &item.UberBlightedMap,
+	} {
+		if strings.HasPrefix(item.BaseType, prefix) {
+			*flag = true
+			item.BaseType = strings.TrimPrefix(item.BaseType, prefix)
+		}
+	}
+	return nil
+}
+
+// applyClipboardProperty sets the item property of a "Key: value" line and reports whether the key is known.
+func applyClipboardProperty(item *Item, key, value string) bool {
+	number := func() int {
+		match := leadingNumber.FindStringSubmatch(value)
+		if match == nil {
+			return 0
+		}
+		n, _ := strconv.Atoi(strings.ReplaceAll(match[1], ",", ""))
+		return n
+	}
+
+	switch key {
+	case "Item Level":
+		item.ItemLevel = number()
+	case "Quality":
+		item.Quality = number()
+	case "Stack Size":
+		item.StackSize = number()
+	case "Map Tier":
+		item.MapTier = number()
+	case "Sockets":
+		item.Sockets = value
+	case "Level":
+		// Outside the requirements, "Level" is the level of a gem.
+		if strings.Contains(item.Class, "Gem") {
+			item.GemLevel = number()
+		}
+	case "Armour", "Evasion Rating", "Energy Shield", "Ward", "Memory Strands":
+		n := number()
+		switch key {
+		case "Armour":
+			item.BaseArmour = n
+		case "Evasion Rating":
+			item.BaseEvasion = n
+		case "Energy Shield":
+			item.BaseEnergyShield = n
+		case "Ward":
+			item.BaseWard = n
+		case "Memory Strands":
+			item.MemoryStrands = n
+		}
+	default:
+		return strings.HasSuffix(key, "Quality") || key == "Note"
+	}
+
+	if strings.HasSuffix(key, "Quality") && key != "Quality" {
+		item.AlternateQuality = true
+	}
+	return true
+}
+
+func applyClipboardFlag(item *Item, line string) bool {
+	if influence, ok := influenceLines[line]; ok {
+		item.Influences = append(item.Influences, influence)
+		return true
+	}
+
+	switch line {
+	case "Corrupted":
+		item.Corrupted = true
+	case "Mirrored":
+		item.Mirrored = true
+	case "Unidentified":
+		item.Unidentified = true
+	case "Fractured Item":
+		item.Fractured = true
+	case "Synthesised Item":
+		item.Synthesised = true
+	case "Scourged":
+		item.Scourged = true
+	case "Searing Exarch Item", "Eater of Worlds Item":
+		// The tiers come from the implicit mod headers.
+	default:
+		return false
+	}
+	return true
+}
+
+// modHeader is an advanced mod header: the mod list it adds to, if any, and whether it named the mod.
+type modHeader struct {
+	mods  *[]string
+	named bool
+}
+
+// applyAdvancedModHeader records the mod named by an advanced mod header, e.g. kind "Prefix " and name "Tyrannical".
+// tier is the tier name of eldritch implicits, e.g. "Greater".
+func applyAdvancedModHeader(item *Item, kind, name, tier string) *modHeader {
+	header := &modHeader{named: name != ""}
+	switch {
+	case strings.HasPrefix(kind, "Searing Exarch Implicit"):
+		item.SearingExarchTier = eldritchTier(tier)
+		return header
+	case strings.HasPrefix(kind, "Eater of Worlds Implicit"):
+		item.EaterOfWorldsTier = eldritchTier(tier)
+		return header
+	case strings.Contains(kind, "Implicit"):
+		header.mods = &item.ImplicitMods
+	case strings.Contains(kind, "Enchant"):
+		header.mods = &item.Enchantments
+	default:
+		header.mods = &item.ExplicitMods
+	}
+
+	if header.named {
+		*header.mods = append(*header.mods, name)
+	}
+	return header
+}
+
+// eldritchTier converts the tier name of an eldritch implicit, e.g. "Greater", to its number.
+// A header without a known tier name counts as the lowest tier.
+func eldritchTier(name string) int {
+	if index := slices.Index([]string{"Lesser", "Greater", "Grand", "Exceptional", "Exquisite", "Perfect"}, name); index >= 0 {
+		return index + 1
+	}
+	return 1
+}
+
+func applySuffixedMod(item *Item, mod, suffix string) {
+	switch suffix {
+	case "implicit":
+		item.ImplicitMods = append(item.ImplicitMods, mod)
+	case "enchant":
+		item.Enchantments = append(item.Enchantments, mod)
+	case "scourge":
+		item.Scourged = true
+	case "fractured":
+		item.Fractured = true
+		item.ExplicitMods = append(item.ExplicitMods, mod)
+	default:
+		item.ExplicitMods = append(item.ExplicitMods, mod)
+	}
+}
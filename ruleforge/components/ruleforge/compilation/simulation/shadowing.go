@@ -0,0 +1,58 @@
+package simulation
+
+import (
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation"
+)
+
+// Shadowing is a block that no item can ever reach, because an earlier block catches every item it would match.
+type Shadowing struct {
+	Shadowed Match
+	By       Match
+}
+
+// FindShadowedBlocks returns the blocks of the filter that are shadowed by an earlier block.
+// A block shadows a later one when all of its conditions are also conditions of the later block.
+// Blocks with Continue let the items they match fall through, so they never shadow anything.
+func FindShadowedBlocks(filter compilation.CompiledFilter) ([]Shadowing, error) {
+	blocks, err := ParseFilter(filter.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	origins := make(map[int]*compilation.BlockOrigin, len(filter.Blocks))
+	for i := range filter.Blocks {
+		origins[filter.Blocks[i].Line] = &filter.Blocks[i].Origin
+	}
+
+	var shadowings []Shadowing
+	for i, block := range blocks {
+		for _, earlier := range blocks[:i] {
+			if earlier.Continue || !conditionsCovered(earlier.Conditions, block.Conditions) {
+				continue
+			}
+			shadowings = append(shadowings, Shadowing{
+				Shadowed: Match{Block: block, Origin: origins[block.Line]},
+				By:       Match{Block: earlier, Origin: origins[earlier.Line]},
+			})
+			break
+		}
+	}
+	return shadowings, nil
+}
+
+// conditionsCovered reports whether every condition of general is also a condition of specific.
+func conditionsCovered(general, specific []Condition) bool {
+	for _, condition := range general {
+		found := false
+		for _, other := range specific {
+			if condition.String() == other.String() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,90 @@
+package simulation
+
+import (
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation"
+)
+
+// Match is a block that caught the item.
+type Match struct {
+	Block Block
+	// Origin is the script construct that generated the block; nil if the filter did not record one.
+	Origin *compilation.BlockOrigin
+}
+
+// Result is the outcome of dropping an item against a filter.
+type Result struct {
+	// Matches are the blocks that caught the item in filter order. All but the last carry Continue.
+	Matches []Match
+	// Visible is false when the deciding block hides the item.
+	Visible bool
+	// Style holds the effective styling lines; later blocks override earlier ones per keyword.
+	Style []string
+	// Warnings describe conditions the simulator could not evaluate.
+	Warnings []string
+}
+
+// Simulate evaluates the item against the blocks of the filter in order, like the game does:
+// the first matching block decides, unless it has Continue, in which case its styling is kept and evaluation carries on.
+// An item that no block catches is shown unstyled.
+func Simulate(filter compilation.CompiledFilter, item Item) (*Result, error) {
+	blocks, err := ParseFilter(filter.Lines)
+	if err != nil {
+		return nil, err
+	}
+
+	origins := make(map[int]*compilation.BlockOrigin, len(filter.Blocks))
+	for i := range filter.Blocks {
+		origins[filter.Blocks[i].Line] = &filter.Blocks[i].Origin
+	}
+
+	result := &Result{Visible: true}
+	for _, block := range blocks {
+		if !blockMatches(block, item, result) {
+			continue
+		}
+
+		result.Matches = append(result.Matches, Match{Block: block, Origin: origins[block.Line]})
+		result.Visible = block.Visibility != "Hide"
+		result.Style = mergeStyle(result.Style, block.Actions)
+		if !block.Continue {
+			break
+		}
+	}
+	return result, nil
+}
+
+func blockMatches(block Block, item Item, result *Result) bool {
+	for _, condition := range block.Conditions {
+		matches, err := condition.Matches(item)
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeStyle applies the actions of a block on top of the style collected so far.
+func mergeStyle(style, actions []string) []string {
+	for _, action := range actions {
+		keyword, _, _ := strings.Cut(action, " ")
+		index := -1
+		for i, existing := range style {
+			if existingKeyword, _, _ := strings.Cut(existing, " "); existingKeyword == keyword {
+				index = i
+				break
+			}
+		}
+
+		if index == -1 {
+			style = append(style, action)
+		} else {
+			style[index] = action
+		}
+	}
+	return style
+}
@@ -0,0 +1,71 @@
+package compilation
+
+import (
+	"fmt"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// csvMacroName is the macro whose parameters select base type automation entries instead of styles.
+const csvMacroName = "handle_csv"
+
+// CheckStyleReferences resolves every style the script refers to, without compiling it.
+// This covers rule styles, macro style parameters, the styles of the CSV entries used by the script and the fallback style.
+// All unresolved styles are returned together as a diagnostics.List.
+func CheckStyleReferences(
+	parseTree *shared.ParseTree[symbols.LexingTokenType],
+	styleJsonPath string,
+	cssVariables map[string]string,
+	baseTypeData []config.BaseTypeAutomationEntry,
+) error {
+	styleManager, err := NewStyleManager(styleJsonPath, parseTree, cssVariables)
+	if err != nil {
+		return err
+	}
+
+	var problems diagnostics.List
+	check := func(position lexshared.Position, styleValue string, context string) {
+		if _, err := styleManager.GetStyle(styleValue); err != nil {
+			problems = append(problems, &diagnostics.Diagnostic{
+				Position: position,
+				Err:      fmt.Errorf("%s: %w", context, err),
+			})
+		}
+	}
+
+	// The fallback rule is not written in the script, so there is no position to point at.
+	check(lexshared.Position{}, "Fallback", "fallback style")
+
+	for _, ruleNode := range parseTree.FindAllSymbolNodes(symbols.ParseSymbolRuleExpression.String()) {
+		styleNode := ruleNode.Children[2]
+		check(styleNode.Position(), styleNode.Token.ValueToString(), "rule style")
+	}
+
+	for _, macroNode := range parseTree.FindAllSymbolNodes(symbols.ParseSymbolMacroExpression.String()) {
+		macroType := macroNode.Children[1].Token.ValueToString()
+		for _, parameter := range macroNode.FindAllSymbolNodes(symbols.ParseSymbolParameter.String()) {
+			key := parameter.Children[1].Token.ValueToString()
+			value := parameter.Children[3].Token.ValueToString()
+
+			if macroType != csvMacroName {
+				check(parameter.Children[3].Position(), value, fmt.Sprintf("style parameter %s of macro %s", key, macroType))
+				continue
+			}
+
+			for _, entry := range baseTypeData {
+				if entry.Category == value {
+					check(parameter.Children[3].Position(), entry.Style, fmt.Sprintf("style of CSV entry %q", entry.BaseType))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
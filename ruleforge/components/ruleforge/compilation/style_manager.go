@@ -2,6 +2,7 @@ package compilation
 
 import (
 	"fmt"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
@@ -13,6 +14,8 @@ type StyleManager struct {
 	styles       map[string]config.Style
 	rootNode     *shared.ParseTree[symbols.LexingTokenType]
 	varNodeCache map[string]*shared.ParseTree[symbols.LexingTokenType]
+	// variables are the script's variables, extracted the first time a style interpolates one.
+	variables map[string][]string
 }
 
 func NewStyleManager(
@@ -23,21 +26,48 @@ func NewStyleManager(
 	if err != nil {
 		return nil, fmt.Errorf("failed to load styles: %w", err)
 	}
+	return NewStyleManagerFromStyles(styles, rootNode), nil
+}
+
+// NewStyleManagerFromStyles creates a StyleManager over already loaded styles.
+// The styles are only read, so one map can be shared by the style managers of several scripts.
+func NewStyleManagerFromStyles(
+	styles map[string]config.Style,
+	rootNode *shared.ParseTree[symbols.LexingTokenType]) *StyleManager {
 	return &StyleManager{
 		styles:       styles,
 		rootNode:     rootNode,
 		varNodeCache: make(map[string]*shared.ParseTree[symbols.LexingTokenType]),
-	}, nil
+	}
 }
 
 // GetStyle resolves a style value, which could be a direct key or a variable.
 func (sm *StyleManager) GetStyle(styleValue string) (*config.Style, error) {
 	if !isVariableRef(styleValue) {
-		return sm.lookupStyle(styleValue)
+		key, err := sm.interpolate(styleValue)
+		if err != nil {
+			return nil, err
+		}
+		return sm.lookupStyle(key)
 	}
 	return sm.resolveVariableStyle(styleValue)
 }
 
+// interpolate replaces the variables interpolated into a style key, e.g. "Tiers/T{$n}".
+func (sm *StyleManager) interpolate(styleValue string) (string, error) {
+	if !strings.Contains(styleValue, "{$") {
+		return styleValue, nil
+	}
+	if sm.variables == nil {
+		variables, err := NewTreeWalker(sm.rootNode).ExtractVariables()
+		if err != nil {
+			return "", err
+		}
+		sm.variables = variables
+	}
+	return model.Interpolate(styleValue, sm.variables, lexshared.Position{})
+}
+
 // resolveVariableStyle is the core of the style resolution logic. It finds a
 // variable's definition in the parse tree and recursively merges its base styles,
 // using any defined overrides to resolve merge conflicts.
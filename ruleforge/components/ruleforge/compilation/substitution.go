@@ -0,0 +1,55 @@
+package compilation
+
+import (
+	"strings"
+
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// substituteReferences copies node with the given names, e.g. macro parameters or loop variables, replaced by their values.
+// A value that is a reference to a name is replaced by the value's token, and `{$name}` within a string by the value's text.
+// Only values are substituted, so the parameter keys of nested macro invocations are kept.
+func substituteReferences(
+	node *shared.ParseTree[symbols.LexingTokenType],
+	values map[string]*lexshared.Token[symbols.LexingTokenType],
+) *shared.ParseTree[symbols.LexingTokenType] {
+	substituted := &shared.ParseTree[symbols.LexingTokenType]{
+		Symbol:   node.Symbol,
+		Token:    node.Token,
+		Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0, len(node.Children)),
+	}
+
+	if node.Symbol == symbols.ParseSymbolValue.String() && node.Token != nil {
+		switch node.Token.Type {
+		case symbols.VariableReferenceToken:
+			if value, ok := values[node.Token.ValueToString()]; ok {
+				substituted.Token = value
+			}
+		case symbols.IdentifierValueToken:
+			substituted.Token = interpolate(node.Token, values)
+		}
+	}
+
+	for _, child := range node.Children {
+		substituted.Children = append(substituted.Children, substituteReferences(child, values))
+	}
+	return substituted
+}
+
+// interpolate returns the string token with every `{$name}` replaced by the text of the name's value.
+func interpolate(
+	token *lexshared.Token[symbols.LexingTokenType],
+	values map[string]*lexshared.Token[symbols.LexingTokenType],
+) *lexshared.Token[symbols.LexingTokenType] {
+	text := token.ValueToString()
+	if !strings.Contains(text, "{$") {
+		return token
+	}
+
+	for name, value := range values {
+		text = strings.ReplaceAll(text, "{"+name+"}", value.ValueToString())
+	}
+	return &lexshared.Token[symbols.LexingTokenType]{Type: token.Type, Value: []byte(text), Position: token.Position}
+}
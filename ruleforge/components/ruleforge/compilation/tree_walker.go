@@ -21,7 +21,8 @@ type ExtractedMetadata struct {
 type ExtractedSection struct {
 	Name        string
 	Description string
-	Conditions  []model2.Condition
+	Strictness  string // Default strictness of the section's rules; empty when not set.
+	Conditions  model2.ConditionExpression
 	// We pass the raw nodes to the RuleGenerator to handle.
 	RuleNodes []*shared.ParseTree[symbols.LexingTokenType]
 }
@@ -66,24 +67,36 @@ func (tw *TreeWalker) ExtractMetadata() ExtractedMetadata {
 	return meta
 }
 
-// ExtractVariables finds all variable declarations and returns them as a map.
-func (tw *TreeWalker) ExtractVariables() map[string][]string {
-	variables := make(map[string][]string)
-	variableNodes := tw.parseTree.FindAllSymbolNodes(symbols.ParseSymbolVariable.String())
+// ExtractVariables finds all variable declarations and returns their values by name.
+// Arithmetic like `$map_start + 5` is evaluated and `{$name}` within strings is interpolated. Other values, like styles
+// joined by `+`, are kept as written. A variable declared more than once takes its last value.
+// Problems, such as type errors, are returned together as a diagnostics.List.
+func (tw *TreeWalker) ExtractVariables() (map[string][]string, error) {
+	evaluator := &variableEvaluator{
+		declarations: make(map[string]*shared.ParseTree[symbols.LexingTokenType]),
+		values:       make(map[string][]string),
+		lists:        make(map[string]bool),
+		failed:       make(map[string]bool),
+	}
 
-	for _, variableNode := range variableNodes {
-		assignmentNodes := variableNode.FindAllSymbolNodes(symbols.ParseSymbolAssignment.String())
-		for _, assignmentNode := range assignmentNodes {
+	var names []string
+	for _, variableNode := range tw.parseTree.FindAllSymbolNodes(symbols.ParseSymbolVariable.String()) {
+		for _, assignmentNode := range variableNode.FindAllSymbolNodes(symbols.ParseSymbolAssignment.String()) {
 			identifier := assignmentNode.Children[1].Token.ValueToString()
-			valueNodes := assignmentNode.FindAllSymbolNodes(symbols.ParseSymbolValue.String())
-			assignments := make([]string, 0, len(valueNodes))
-			for _, valueNode := range valueNodes {
-				assignments = append(assignments, valueNode.Token.ValueToString())
+			if _, declared := evaluator.declarations[identifier]; !declared {
+				names = append(names, identifier)
 			}
-			variables[identifier] = assignments
+			evaluator.declarations[identifier] = assignmentNode
 		}
 	}
-	return variables
+
+	for _, name := range names {
+		evaluator.evaluate(name)
+	}
+	if len(evaluator.problems) > 0 {
+		return nil, evaluator.problems
+	}
+	return evaluator.values, nil
 }
 
 // ExtractSections finds all section blocks and extracts their data into a slice.
@@ -97,6 +110,7 @@ func (tw *TreeWalker) ExtractSections() []ExtractedSection {
 
 		sectionName := "<unknown>"
 		sectionDescription := "<unknown>"
+		sectionStrictness := ""
 		for _, assignment := range assignments {
 			key, value := extractAssignmentKeyAndValue(assignment)
 			switch key {
@@ -104,21 +118,28 @@ func (tw *TreeWalker) ExtractSections() []ExtractedSection {
 				sectionName = value
 			case "DESCRIPTION":
 				sectionDescription = value
+			case "STRICTNESS":
+				sectionStrictness = value
 			}
 		}
 
-		conditionListNode := sectionNode.FindSymbolNode(symbols.ParseSymbolConditionList.String())
-		sectionConditions := retrieveConditions(conditionListNode)
+		// IF blocks can contribute SECTION_CONDITIONS and RULES blocks of their own, so a section can have several of each.
+		sectionConditions := model2.ConditionExpression{Operator: model2.LogicalAnd}
+		for _, conditionListNode := range sectionNode.FindAllSymbolNodes(symbols.ParseSymbolConditionList.String()) {
+			sectionConditions.Operands = append(sectionConditions.Operands, retrieveConditionExpression(conditionListNode).Operands...)
+		}
 
-		ruleListNode := sectionNode.FindSymbolNode(symbols.ParseSymbolRules.String())
 		var ruleNodes []*shared.ParseTree[symbols.LexingTokenType]
-		if ruleListNode != nil {
-			ruleNodes = ruleListNode.Children
+		for _, ruleSectionNode := range sectionNode.FindAllSymbolNodes(symbols.ParseSymbolRuleSection.String()) {
+			if ruleListNode := ruleSectionNode.FindSymbolNode(symbols.ParseSymbolRules.String()); ruleListNode != nil {
+				ruleNodes = append(ruleNodes, ruleListNode.Children...)
+			}
 		}
 
 		extracted = append(extracted, ExtractedSection{
 			Name:        sectionName,
 			Description: sectionDescription,
+			Strictness:  sectionStrictness,
 			Conditions:  sectionConditions,
 			RuleNodes:   ruleNodes,
 		})
@@ -138,27 +159,102 @@ func extractAssignmentKeyAndValue(assignment *shared.ParseTree[symbols.LexingTok
 	return key, value
 }
 
-// retrieveConditions extracts all raw condition expressions from a given node.
-func retrieveConditions(node *shared.ParseTree[symbols.LexingTokenType]) []model2.Condition {
+// retrieveConditionExpression extracts the WHERE clauses below node. Separate clauses, like those of SECTION_CONDITIONS, must all hold.
+func retrieveConditionExpression(node *shared.ParseTree[symbols.LexingTokenType]) model2.ConditionExpression {
+	expression := model2.ConditionExpression{Operator: model2.LogicalAnd}
 	if node == nil {
-		return nil
+		return expression
+	}
+	if node.Symbol == symbols.ParseSymbolCondition.String() {
+		expression.Operands = append(expression.Operands, parseConditionClause(node))
+		return expression
+	}
+	for _, child := range node.Children {
+		if child.Symbol == symbols.ParseSymbolCondition.String() || len(child.Children) > 0 {
+			expression.Operands = append(expression.Operands, retrieveConditionExpression(child).Operands...)
+		}
 	}
-	conditionNodes := node.FindAllSymbolNodes(symbols.ParseSymbolConditionExpression.String())
-	conditions := make([]model2.Condition, len(conditionNodes))
+	return expression
+}
 
-	for i, conditionNode := range conditionNodes {
-		if len(conditionNode.Children) < 4 {
-			continue
+// parseConditionClause converts a Condition node into an expression.
+// `->` binds tighter than `|`, so the terms are first collected into AND groups, which are then combined with OR.
+func parseConditionClause(node *shared.ParseTree[symbols.LexingTokenType]) model2.ConditionExpression {
+	var terms []*shared.ParseTree[symbols.LexingTokenType]
+	for _, child := range node.Children {
+		if child.Symbol == symbols.ParseSymbolChainedConditions.String() {
+			terms = append(terms, child.Children...)
+		} else {
+			terms = append(terms, child)
 		}
-		identifier := conditionNode.Children[1].Token.ValueToString()
-		operator := conditionNode.Children[2].Token.ValueToString()
-		value := conditionNode.Children[3].Token.ValueToString()
-
-		conditions[i] = model2.Condition{
-			Identifier: identifier,
-			Operator:   operator,
-			Value:      []string{value},
+	}
+
+	alternatives := model2.ConditionExpression{Operator: model2.LogicalOr}
+	current := model2.ConditionExpression{Operator: model2.LogicalAnd}
+	for i, term := range terms {
+		if i > 0 && conditionConnector(term) == symbols.AlternationOperatorToken {
+			alternatives.Operands = append(alternatives.Operands, current)
+			current = model2.ConditionExpression{Operator: model2.LogicalAnd}
 		}
+		current.Operands = append(current.Operands, parseConditionTerm(term))
+	}
+	alternatives.Operands = append(alternatives.Operands, current)
+
+	if len(alternatives.Operands) == 1 {
+		return alternatives.Operands[0]
+	}
+	return alternatives
+}
+
+// parseConditionTerm converts a single condition or a parenthesized group.
+func parseConditionTerm(term *shared.ParseTree[symbols.LexingTokenType]) model2.ConditionExpression {
+	if term.Symbol == symbols.ParseSymbolConditionGroup.String() {
+		return parseConditionClause(term.Children[len(term.Children)-1])
+	}
+
+	// The connector is absent for the first condition inside a group, so the parts are taken from the end.
+	parts := term.Children[len(term.Children)-3:]
+	operator, count := conditionOperator(parts[1])
+	condition := model2.Condition{
+		Identifier: parts[0].Token.ValueToString(),
+		Operator:   operator,
+		Count:      count,
+		Position:   parts[0].Position(),
+	}
+	if parts[2].Symbol == symbols.ParseSymbolFullValueExpression.String() {
+		condition.Expression = buildExpression(parts[2])
+	} else {
+		condition.Value = conditionValues(parts[2])
+	}
+	return model2.ConditionLeaf(condition)
+}
+
+// conditionOperator returns the operator of a condition and, for counted operators like `>=2`, the count.
+func conditionOperator(node *shared.ParseTree[symbols.LexingTokenType]) (string, string) {
+	if node.Symbol == symbols.ParseSymbolCountedOperator.String() {
+		return node.Children[0].Token.ValueToString(), node.Children[1].Token.ValueToString()
+	}
+	return node.Token.ValueToString(), ""
+}
+
+// conditionValues returns the value of a condition, or every value of a bracketed list.
+func conditionValues(node *shared.ParseTree[symbols.LexingTokenType]) []string {
+	if node.Symbol != symbols.ParseSymbolValueList.String() {
+		return []string{node.Token.ValueToString()}
+	}
+
+	valueNodes := node.FindAllSymbolNodes(symbols.ParseSymbolValue.String())
+	values := make([]string, 0, len(valueNodes))
+	for _, valueNode := range valueNodes {
+		values = append(values, valueNode.Token.ValueToString())
+	}
+	return values
+}
+
+// conditionConnector returns the token joining a term to the previous one: WHERE, `->` or `|`.
+func conditionConnector(term *shared.ParseTree[symbols.LexingTokenType]) symbols.LexingTokenType {
+	if len(term.Children) == 0 || term.Children[0].Token == nil {
+		return symbols.IgnoreToken
 	}
-	return conditions
+	return term.Children[0].Token.Type
 }
@@ -233,34 +233,36 @@ func (b *Build) AssociatedWeaponClasses() []string {
 }
 
 // IsWeaponAssociated checks if an ItemBase's weapon type matches the build preset.
-func (b *Build) IsWeaponAssociated(item model.ItemBase) bool {
+// It fails for weapon types it does not know, e.g. ones added to PoB after this list.
+func (b *Build) IsWeaponAssociated(item model.ItemBase) (bool, error) {
 	wc, ok := pobTypeToWeaponClass[item.Type]
 	if item.Type == string(OneHandedSword) && item.SubType == "Thrusting" {
 		wc = ThrustingOneHandedSword
 	}
 	if !ok {
-		panic("unknown weapon type: " + item.Type)
+		return false, fmt.Errorf("unknown weapon type %q of %s", item.Type, item.Name)
 	}
-	return slices.Contains(b.Preset.WeaponClasses, wc)
+	return slices.Contains(b.Preset.WeaponClasses, wc), nil
 }
 
 // IsArmorAssociated checks if an ItemBase's armor type matches the build preset.
-func (b *Build) IsArmorAssociated(item model.ItemBase) bool {
+// It fails for armor classes and subtypes it does not know.
+func (b *Build) IsArmorAssociated(item model.ItemBase, logger *log.Logger) (bool, error) {
 	ac, ok := pobTypeToArmorClass[item.Type]
 	if !ok {
-		panic("unknown armor class: " + item.Type)
+		return false, fmt.Errorf("unknown armor class %q of %s", item.Type, item.Name)
 	}
 	// Belts are always included.
 	if ac == Belt {
-		return true
+		return true, nil
 	}
 	if item.SubType == "" {
-		log.Printf("WARNING: Empty armor subtype, including by default: %s (%s)\n", item.Name, item.Type)
-		return true
+		logger.Printf("WARNING: Empty armor subtype, including by default: %s (%s)\n", item.Name, item.Type)
+		return true, nil
 	}
 	at, ok := pobArmorTypeToArmorType[item.SubType]
 	if !ok {
-		panic("unknown armor subtype: " + item.SubType)
+		return false, fmt.Errorf("unknown armor subtype %q of %s", item.SubType, item.Name)
 	}
-	return slices.Contains(b.Preset.ArmorTypes, at)
+	return slices.Contains(b.Preset.ArmorTypes, at), nil
 }
@@ -30,6 +30,10 @@ type ConfigurationModel struct {
 	// RuleforgeInputDir specifies the directory which filter(s) should be processed.
 	RuleforgeInputDir string `json:"RuleforgeInputDir"`
 
+	// ImportSearchPaths are the directories searched, in order, for imported files that are not next to the importing file.
+	// RuleforgeInputDir is always searched last.
+	ImportSearchPaths []string `json:"ImportSearchPaths"`
+
 	// StyleJSONFile indicates the JSON file where all styles are housed.
 	StyleJSONFile string `json:"StyleJSONFile"`
 
@@ -56,6 +60,16 @@ type ConfigurationModel struct {
 
 	// CustomEquipmentPresets lets you specify named presets with weapons/armour lists.
 	CustomEquipmentPresets map[string]EquipmentPreset `json:"CustomEquipmentPresets"`
+
+	// CacheDir is the directory holding the item and economy caches. It defaults to "./cache".
+	CacheDir string `json:"CacheDir"`
+
+	// EconomyCachePolicy decides whether expired caches are used and whether poe.ninja may be contacted:
+	// "strict" (default), "allow-stale" or "offline".
+	EconomyCachePolicy string `json:"EconomyCachePolicy"`
+
+	// EconomySnapshotFile optionally points at an economy cache file to use instead of the economy cache.
+	EconomySnapshotFile string `json:"EconomySnapshotFile"`
 }
 
 func (c *ConfigurationModel) String() string {
@@ -75,6 +89,9 @@ func (c *ConfigurationModel) String() string {
 	}
 
 	sb.WriteString(fmt.Sprintf("\n📥 Ruleforge Input Dir: %s\n", c.RuleforgeInputDir))
+	if len(c.ImportSearchPaths) > 0 {
+		sb.WriteString(fmt.Sprintf("🔎 Import Search Paths: %s\n", strings.Join(c.ImportSearchPaths, ", ")))
+	}
 	sb.WriteString(fmt.Sprintf("🎨 Style JSON File:     %s\n", c.StyleJSONFile))
 	sb.WriteString(fmt.Sprintf("🎨 Style Color CSS:     %s\n", c.StyleColorCSSFile))
 	sb.WriteString(fmt.Sprintf("📊 BaseType CSV File:   %s\n", c.BaseTypeCSVFile))
@@ -97,6 +114,11 @@ func (c *ConfigurationModel) String() string {
 
 	sb.WriteString(fmt.Sprintf("\n📈 Normalization Strategy: %s\n", c.EconomyNormalizationStrategy))
 	sb.WriteString(fmt.Sprintf("⚖️  Chase vs General Factor: %.2f\n", c.ChaseVSGeneralPotentialFactor))
+	sb.WriteString(fmt.Sprintf("🗄️  Cache Dir:              %s\n", c.GetCacheDir()))
+	sb.WriteString(fmt.Sprintf("🗄️  Economy Cache Policy:   %s\n", c.GetEconomyCachePolicy()))
+	if c.EconomySnapshotFile != "" {
+		sb.WriteString(fmt.Sprintf("📸 Economy Snapshot:       %s\n", c.EconomySnapshotFile))
+	}
 
 	sb.WriteString("\n🧩 Custom Equipment Presets:\n")
 	if len(c.CustomEquipmentPresets) > 0 {
@@ -117,6 +139,33 @@ var validNormalizationStrategies = []string{
 	"Per-League",
 }
 
+var validEconomyCachePolicies = []string{
+	"strict",
+	"allow-stale",
+	"offline",
+}
+
+// GetCacheDir returns the configured cache directory, "./cache" if none is set.
+func (c *ConfigurationModel) GetCacheDir() string {
+	if c.CacheDir == "" {
+		return "./cache"
+	}
+	return c.CacheDir
+}
+
+// GetImportSearchPaths returns the directories searched for imports, ending with RuleforgeInputDir.
+func (c *ConfigurationModel) GetImportSearchPaths() []string {
+	return append(slices.Clone(c.ImportSearchPaths), c.RuleforgeInputDir)
+}
+
+// GetEconomyCachePolicy returns the configured economy cache policy, "strict" if none is set.
+func (c *ConfigurationModel) GetEconomyCachePolicy() string {
+	if c.EconomyCachePolicy == "" {
+		return "strict"
+	}
+	return c.EconomyCachePolicy
+}
+
 func (c *ConfigurationModel) GetLeagueWeights() []LeagueWeights {
 	leagueWeights := make([]LeagueWeights, 0)
 
@@ -137,6 +186,11 @@ func (c *ConfigurationModel) Validate() error {
 		return fmt.Errorf("invalid normalization strategy '%s', expected one of: %s", c.EconomyNormalizationStrategy, validString)
 	}
 
+	if !slices.Contains(validEconomyCachePolicies, c.GetEconomyCachePolicy()) {
+		validString := extensions.GetFormattedString(validEconomyCachePolicies)
+		return fmt.Errorf("invalid economy cache policy '%s', expected one of: %s", c.EconomyCachePolicy, validString)
+	}
+
 	totalEconomyWeights := c.EconomyWeights.Rarity + c.EconomyWeights.Value
 
 	if totalEconomyWeights != 1.0 {
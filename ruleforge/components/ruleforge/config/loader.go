@@ -2,7 +2,6 @@ package config
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 )
 
@@ -29,7 +28,5 @@ func (c *ConfigurationLoader) LoadConfiguration(file string) (*ConfigurationMode
 		return nil, err
 	}
 
-	fmt.Println(cfg.String())
-
 	return &cfg, nil
 }
@@ -0,0 +1,263 @@
+package data_generation
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// CacheKind names one of the caches kept by the CacheRepository.
+type CacheKind string
+
+const (
+	CacheKindItems   CacheKind = "items"
+	CacheKindEconomy CacheKind = "economy"
+)
+
+// AllCacheKinds returns every cache kind, items first.
+func AllCacheKinds() []CacheKind {
+	return []CacheKind{CacheKindItems, CacheKindEconomy}
+}
+
+// ParseCacheKind converts a command-line argument into a CacheKind.
+func ParseCacheKind(value string) (CacheKind, error) {
+	kind := CacheKind(value)
+	if !slices.Contains(AllCacheKinds(), kind) {
+		return "", fmt.Errorf("unknown cache %q, expected items or economy", value)
+	}
+	return kind, nil
+}
+
+// CacheStatus describes a cache file.
+type CacheStatus struct {
+	Kind       CacheKind
+	Path       string
+	Exists     bool
+	Size       int64
+	ModTime    time.Time
+	ExpiryDate time.Time
+	// Contents breaks the cache down: the item cache per kind of data, the economy cache per league.
+	Contents []CacheContent
+}
+
+// CacheContent is a part of a cache, with its number of entries and its encoded size in bytes.
+type CacheContent struct {
+	Name  string
+	Count int
+	Size  int64
+}
+
+// Age returns how long ago the cache was written.
+func (s CacheStatus) Age() time.Duration {
+	return time.Since(s.ModTime)
+}
+
+// IsExpired reports whether the cache is past its expiry date.
+func (s CacheStatus) IsExpired() bool {
+	return !time.Now().Before(s.ExpiryDate)
+}
+
+// Path returns the file of the given cache.
+func (c *CacheRepository) Path(kind CacheKind) string {
+	if kind == CacheKindItems {
+		return c.baseTypeCachePath
+	}
+	return c.economyCacheRawPath
+}
+
+// Status describes every cache, in the order of AllCacheKinds. Missing caches are reported with Exists false.
+func (c *CacheRepository) Status() ([]CacheStatus, error) {
+	itemCache, economyCache, err := c.LoadCache()
+	if err != nil {
+		return nil, fmt.Errorf("loading caches: %w", err)
+	}
+
+	statuses := make([]CacheStatus, 0, len(AllCacheKinds()))
+	for _, kind := range AllCacheKinds() {
+		status := CacheStatus{Kind: kind, Path: c.Path(kind)}
+
+		info, err := os.Stat(status.Path)
+		if errors.Is(err, os.ErrNotExist) {
+			statuses = append(statuses, status)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		status.Exists = true
+		status.Size = info.Size()
+		status.ModTime = info.ModTime()
+
+		switch kind {
+		case CacheKindItems:
+			status.ExpiryDate = itemCache.ExpiryDate
+			status.Contents = []CacheContent{
+				contentOf("item bases", itemCache.Items),
+				contentOf("essences", itemCache.Essences),
+				contentOf("gems", itemCache.Gems),
+				contentOf("uniques", itemCache.Uniques),
+			}
+		case CacheKindEconomy:
+			status.ExpiryDate = economyCache.ExpiryDate
+			for _, league := range slices.Sorted(maps.Keys(economyCache.EconomyCacheItems)) {
+				status.Contents = append(status.Contents, contentOf(league, economyCache.EconomyCacheItems[league]))
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func contentOf[T any](name string, entries []T) CacheContent {
+	encoded, _ := json.Marshal(entries)
+	return CacheContent{Name: name, Count: len(entries), Size: int64(len(encoded))}
+}
+
+// Clear deletes the given caches and returns the files that were removed. Missing caches are skipped.
+func (c *CacheRepository) Clear(kinds ...CacheKind) ([]string, error) {
+	removed := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		path := c.Path(kind)
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return removed, fmt.Errorf("removing %s cache: %w", kind, err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
+
+// Export writes the existing caches into a zip archive at archivePath and returns the kinds it exported.
+func (c *CacheRepository) Export(archivePath string) ([]CacheKind, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	exported := make([]CacheKind, 0)
+	for _, kind := range AllCacheKinds() {
+		data, err := os.ReadFile(c.Path(kind))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry, err := archive.Create(filepath.Base(c.Path(kind)))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := entry.Write(data); err != nil {
+			return nil, err
+		}
+		exported = append(exported, kind)
+	}
+
+	if len(exported) == 0 {
+		return nil, fmt.Errorf("there are no caches in %s to export", filepath.Dir(c.baseTypeCachePath))
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return exported, os.WriteFile(archivePath, buffer.Bytes(), 0644)
+}
+
+// Import replaces the caches with the ones in a zip archive written by Export and returns the kinds it imported.
+// Every cache in the archive is validated before any cache is replaced.
+func (c *CacheRepository) Import(archivePath string) ([]CacheKind, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	caches := make(map[CacheKind][]byte)
+	for _, file := range archive.File {
+		kind, err := c.kindOfFile(file.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := readZipFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file.Name, err)
+		}
+		if err := validateCache(kind, data); err != nil {
+			return nil, fmt.Errorf("%s is not a valid %s cache: %w", file.Name, kind, err)
+		}
+		caches[kind] = data
+	}
+
+	imported := make([]CacheKind, 0, len(caches))
+	for _, kind := range AllCacheKinds() {
+		data, ok := caches[kind]
+		if !ok {
+			continue
+		}
+		if err := writeFileAtomically(c.Path(kind), data); err != nil {
+			return imported, fmt.Errorf("writing %s cache: %w", kind, err)
+		}
+		imported = append(imported, kind)
+	}
+	return imported, nil
+}
+
+func (c *CacheRepository) kindOfFile(name string) (CacheKind, error) {
+	for _, kind := range AllCacheKinds() {
+		if name == filepath.Base(c.Path(kind)) {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("unexpected file %q in cache archive", name)
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func validateCache(kind CacheKind, data []byte) error {
+	if kind == CacheKindItems {
+		var cache ItemCacheModel
+		return json.Unmarshal(data, &cache)
+	}
+	var cache EconomyCacheModel
+	return json.Unmarshal(data, &cache)
+}
+
+// writeFileAtomically writes data next to path and renames it into place, so a failed write never leaves a truncated cache.
+func writeFileAtomically(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
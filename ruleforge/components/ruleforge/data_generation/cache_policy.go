@@ -0,0 +1,55 @@
+package data_generation
+
+import (
+	"fmt"
+	"log"
+)
+
+// CachePolicy decides whether expired caches may be used and whether the exporter may go online.
+type CachePolicy string
+
+const (
+	// CachePolicyStrict regenerates expired caches, fetching from the network where needed.
+	CachePolicyStrict CachePolicy = "strict"
+	// CachePolicyAllowStale uses expired caches with a warning. Missing caches are still fetched.
+	CachePolicyAllowStale CachePolicy = "allow-stale"
+	// CachePolicyOffline uses expired caches with a warning and never touches the network.
+	// Data that is neither cached nor available locally is an error.
+	CachePolicyOffline CachePolicy = "offline"
+)
+
+// ExporterOptions configures how the PathOfBuildingExporter uses its caches.
+type ExporterOptions struct {
+	// CacheDir is the directory holding the caches. It defaults to DefaultCacheDir.
+	CacheDir string
+
+	// CachePolicy defaults to CachePolicyStrict.
+	CachePolicy CachePolicy
+
+	// EconomySnapshotPath is an economy cache file to use instead of the economy cache.
+	// The snapshot is used as is, whatever its expiry date and the cache policy.
+	EconomySnapshotPath string
+}
+
+// acceptsCache reports whether a loaded cache may be used, warning when an expired cache is used anyway.
+func (e *PathOfBuildingExporter) acceptsCache(name string, expired bool) bool {
+	if !expired {
+		return true
+	}
+	if e.cachePolicy == CachePolicyStrict {
+		return false
+	}
+
+	log.Printf("WARN: The %s cache has expired; using it anyway because the cache policy is %s.", name, e.cachePolicy)
+	return true
+}
+
+// CanFetchEconomyData reports whether economy data may be fetched from poe.ninja.
+// It cannot when an economy snapshot is used or when the exporter is offline.
+func (e *PathOfBuildingExporter) CanFetchEconomyData() bool {
+	return e.economySnapshotPath == "" && e.cachePolicy != CachePolicyOffline
+}
+
+func (e *PathOfBuildingExporter) offlineError(data string) error {
+	return fmt.Errorf("no usable %s cache and the cache policy is %s, so it cannot be fetched", data, e.cachePolicy)
+}
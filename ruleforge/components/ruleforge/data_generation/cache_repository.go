@@ -39,6 +39,15 @@ type CacheRepository struct {
 	economyCacheRawPath string
 }
 
+// DefaultCacheDir is the cache directory used when none is configured, relative to the working directory.
+const DefaultCacheDir = "./cache"
+
+// File names of the caches inside the cache directory.
+const (
+	ItemCacheFileName    = "basetypes.json"
+	EconomyCacheFileName = "economy_cache.json"
+)
+
 func NewCacheRepository(baseTypeCachePath, economyCachePath string) *CacheRepository {
 	return &CacheRepository{
 		baseTypeCachePath:   baseTypeCachePath,
@@ -46,9 +55,28 @@ func NewCacheRepository(baseTypeCachePath, economyCachePath string) *CacheReposi
 	}
 }
 
+// NewCacheRepositoryInDir creates a CacheRepository for the caches in dir, or in DefaultCacheDir if dir is empty.
+func NewCacheRepositoryInDir(dir string) *CacheRepository {
+	if dir == "" {
+		dir = DefaultCacheDir
+	}
+	return NewCacheRepository(filepath.Join(dir, ItemCacheFileName), filepath.Join(dir, EconomyCacheFileName))
+}
+
+// IsExpired reports whether the item cache is past its expiry date.
+func (c *ItemCacheModel) IsExpired() bool {
+	return !time.Now().Before(c.ExpiryDate)
+}
+
+// IsExpired reports whether the economy cache is past its expiry date.
+func (c *EconomyCacheModel) IsExpired() bool {
+	return !time.Now().Before(c.ExpiryDate)
+}
+
 // LoadCache reads both the item and economy cache files and unmarshals them.
-// It returns a nil value for any cache that doesn't exist or has expired,
-// without returning an error. An error is only returned for actual file I/O or JSON parsing issues.
+// It returns a nil value for any cache that doesn't exist, without returning an error.
+// Expired caches are returned as well; whether they may still be used is up to the caller.
+// An error is only returned for actual file I/O or JSON parsing issues.
 func (c *CacheRepository) LoadCache() (*ItemCacheModel, *EconomyCacheModel, error) {
 	var itemCache *ItemCacheModel
 	var economyCache *EconomyCacheModel
@@ -68,10 +96,7 @@ func (c *CacheRepository) LoadCache() (*ItemCacheModel, *EconomyCacheModel, erro
 		if err := json.Unmarshal(data, &cache); err != nil {
 			return nil, nil, err
 		}
-		// Only use the cache if it's not expired
-		if time.Now().Before(cache.ExpiryDate) {
-			itemCache = &cache
-		}
+		itemCache = &cache
 	}
 
 	// 2. Attempt to load the economy cache
@@ -80,23 +105,28 @@ func (c *CacheRepository) LoadCache() (*ItemCacheModel, *EconomyCacheModel, erro
 		return nil, nil, fmt.Errorf("error checking for economy cache: %w", err)
 	}
 	if economyCacheExists {
-		data, err := os.ReadFile(c.economyCacheRawPath)
+		economyCache, err = LoadEconomySnapshot(c.economyCacheRawPath)
 		if err != nil {
 			return nil, nil, err
 		}
-		var cache EconomyCacheModel
-		if err := json.Unmarshal(data, &cache); err != nil {
-			return nil, nil, err
-		}
-		// Only use the cache if it's not expired
-		if time.Now().Before(cache.ExpiryDate) {
-			economyCache = &cache
-		}
 	}
 
 	return itemCache, economyCache, nil
 }
 
+// LoadEconomySnapshot reads an economy cache file, e.g. a copy of the economy cache kept for offline builds.
+func LoadEconomySnapshot(path string) (*EconomyCacheModel, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cache EconomyCacheModel
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("parsing economy cache %s: %w", path, err)
+	}
+	return &cache, nil
+}
+
 // SaveItemCache saves the item, essence, gem, and unique data to the item cache file.
 func (c *CacheRepository) SaveItemCache(items []model.ItemBase, essences []model.Essence, gems []model.Gem, uniques []model.Unique) error {
 	expiryDate := time.Now().AddDate(0, 0, 14)
@@ -6,7 +6,9 @@ import (
 	"github.com/muesli/clusters"
 	"github.com/muesli/kmeans"
 	"log"
+	"maps"
 	"math"
+	"slices"
 	"sort"
 )
 
@@ -28,6 +30,17 @@ type TieringParameters struct {
 	ChaosOutlierPercentile   float64
 	MinListingsForPercentile int
 	ChasePotentialWeight     float64
+
+	// Logger receives the tiering warnings. It defaults to the standard logger.
+	Logger *log.Logger
+}
+
+// logger returns the configured logger or the standard logger.
+func (p TieringParameters) logger() *log.Logger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return log.Default()
 }
 
 // TieringDataPoint is a one-dimensional data point for K-Means clustering.
@@ -100,7 +113,7 @@ func GenerateTiers(
 	}
 
 	// --- Normalization Step ---
-	allNormalizedData, err := normalizeData(aggregatedData, params.NormStrategy)
+	allNormalizedData, err := normalizeData(aggregatedData, params.NormStrategy, params.logger())
 	if err != nil {
 		return nil, fmt.Errorf("failed during normalization: %w", err)
 	}
@@ -109,10 +122,13 @@ func GenerateTiers(
 	}
 
 	// --- Scoring Step ---
-	unifiedScores := calculateUnifiedScores(allNormalizedData, params)
+	unifiedScores, err := calculateUnifiedScores(allNormalizedData, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed during scoring: %w", err)
+	}
 
 	// --- Priority Assignment Step ---
-	basetypeToTierMap, err := assignTiers(unifiedScores, numTiers)
+	basetypeToTierMap, err := assignTiers(unifiedScores, numTiers, params.logger())
 	if err != nil {
 		return nil, fmt.Errorf("failed to assign tiers: %w", err)
 	}
@@ -131,22 +147,23 @@ func GenerateTiers(
 
 // --- Pipeline Helper Functions ---
 
-func normalizeData(aggregatedData map[string]map[string]aggregatedItem, strategy NormalizationStrategy) (map[string]map[string]normalizedItem, error) {
+func normalizeData(aggregatedData map[string]map[string]aggregatedItem, strategy NormalizationStrategy, logger *log.Logger) (map[string]map[string]normalizedItem, error) {
 	switch strategy {
 	case Global:
 		return normalizeGlobally(aggregatedData)
 	case PerLeague:
-		return normalizePerLeague(aggregatedData)
+		return normalizePerLeague(aggregatedData, logger)
 	default:
 		return nil, fmt.Errorf("unknown normalization strategy: %s", strategy)
 	}
 }
 
-func normalizePerLeague(aggregatedData map[string]map[string]aggregatedItem) (map[string]map[string]normalizedItem, error) {
+func normalizePerLeague(aggregatedData map[string]map[string]aggregatedItem, logger *log.Logger) (map[string]map[string]normalizedItem, error) {
 	allNormData := make(map[string]map[string]normalizedItem)
-	for league, aggMap := range aggregatedData {
+	for _, league := range slices.Sorted(maps.Keys(aggregatedData)) {
+		aggMap := aggregatedData[league]
 		if len(aggMap) < 2 {
-			log.Printf("WARN: Not enough data points in league %s to normalize, skipping.", league)
+			logger.Printf("WARN: Not enough data points in league %s to normalize, skipping.", league)
 			continue
 		}
 
@@ -235,7 +252,7 @@ func normalizeGlobally(aggregatedData map[string]map[string]aggregatedItem) (map
 func calculateUnifiedScores(
 	allNormData map[string]map[string]normalizedItem,
 	params TieringParameters,
-) []finalScoredItem {
+) ([]finalScoredItem, error) {
 	type unifiedScoreTracker struct {
 		weightedChaosSum  float64
 		weightedRaritySum float64
@@ -245,7 +262,10 @@ func calculateUnifiedScores(
 
 	// Apply weighted sum
 	for league, leagueData := range allNormData {
-		weight := getWeightFromLeague(league, params.LeagueWeights)
+		weight, err := getWeightFromLeague(league, params.LeagueWeights)
+		if err != nil {
+			return nil, err
+		}
 		for bt, normItem := range leagueData {
 			tracker := unified[bt]
 			tracker.weightedChaosSum += normItem.chaosZScore * weight
@@ -268,12 +288,12 @@ func calculateUnifiedScores(
 		score := (params.ValueWeight * avgChaosZ) + (params.RarityWeight * avgRarityZ)
 		finalScores = append(finalScores, finalScoredItem{baseType: bt, score: score})
 	}
-	return finalScores
+	return finalScores, nil
 }
 
-func assignTiers(scoredItems []finalScoredItem, numTiers int) (map[string]int, error) {
+func assignTiers(scoredItems []finalScoredItem, numTiers int, logger *log.Logger) (map[string]int, error) {
 	if len(scoredItems) < numTiers {
-		log.Printf("WARN: Number of items (%d) is less than number of tiers (%d). Reducing tiers.", len(scoredItems), numTiers)
+		logger.Printf("WARN: Number of items (%d) is less than number of tiers (%d). Reducing tiers.", len(scoredItems), numTiers)
 		numTiers = len(scoredItems)
 	}
 	if numTiers == 0 {
@@ -427,14 +447,14 @@ func calculateRobustValue(chaosValues []float64, params TieringParameters) float
 	return chaosValues[medianIndex]
 }
 
-func getWeightFromLeague(league string, leagueWeights []config.LeagueWeights) float64 {
+func getWeightFromLeague(league string, leagueWeights []config.LeagueWeights) (float64, error) {
 	for _, leagueWeight := range leagueWeights {
 		if leagueWeight.League == league {
-			return leagueWeight.Weight
+			return leagueWeight.Weight, nil
 		}
 	}
 
-	panic("no weight found for league: " + league)
+	return 0, fmt.Errorf("no weight found for league %q, add it to the league weights of the configuration", league)
 }
 
 // calculateMeanStdDev is a helper for the global normalization.
@@ -17,23 +17,54 @@ type PathOfBuildingExporter struct {
 	baseTypeCache  *ItemCacheModel
 	economyCache   *EconomyCacheModel
 	cacheRepo      *CacheRepository
+
+	cachePolicy         CachePolicy
+	economySnapshotPath string
 }
 
+// NewPathOfBuildingExporter creates an exporter with the strict cache policy.
 func NewPathOfBuildingExporter() *PathOfBuildingExporter {
-	cacheRepository := NewCacheRepository("./cache/basetypes.json", "./cache/economy_cache.json")
+	exporter, _ := NewPathOfBuildingExporterWithOptions(ExporterOptions{CachePolicy: CachePolicyStrict})
+	return exporter
+}
+
+// NewPathOfBuildingExporterWithOptions creates an exporter that uses its caches as the options dictate.
+// It only fails if the economy snapshot cannot be loaded.
+func NewPathOfBuildingExporterWithOptions(options ExporterOptions) (*PathOfBuildingExporter, error) {
+	cacheRepository := NewCacheRepositoryInDir(options.CacheDir)
 	baseTypeCache, economyCache, err := cacheRepository.LoadCache()
 
 	if err != nil {
 		log.Printf("WARN: Could not load baseTypeCache: %v. Will regenerate data.", err)
 	}
 
-	return &PathOfBuildingExporter{
+	exporter := &PathOfBuildingExporter{
 		luaExecutor:    NewLuaExecutor(),
 		economyScraper: NewPoeNinjaClient(),
-		baseTypeCache:  baseTypeCache,
 		cacheRepo:      cacheRepository,
-		economyCache:   economyCache,
+		cachePolicy:    options.CachePolicy,
+	}
+	if exporter.cachePolicy == "" {
+		exporter.cachePolicy = CachePolicyStrict
+	}
+
+	if baseTypeCache != nil && exporter.acceptsCache("item", baseTypeCache.IsExpired()) {
+		exporter.baseTypeCache = baseTypeCache
 	}
+
+	if options.EconomySnapshotPath != "" {
+		snapshot, err := LoadEconomySnapshot(options.EconomySnapshotPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading economy snapshot: %w", err)
+		}
+		log.Printf("Using economy snapshot %s (expiry date %s).", options.EconomySnapshotPath, snapshot.ExpiryDate.Format(time.DateTime))
+		exporter.economyCache = snapshot
+		exporter.economySnapshotPath = options.EconomySnapshotPath
+	} else if economyCache != nil && exporter.acceptsCache("economy", economyCache.IsExpired()) {
+		exporter.economyCache = economyCache
+	}
+
+	return exporter, nil
 }
 
 func (e *PathOfBuildingExporter) LoadItemBases(luaFilePaths []string) ([]model.ItemBase, error) {
@@ -41,6 +72,11 @@ func (e *PathOfBuildingExporter) LoadItemBases(luaFilePaths []string) ([]model.I
 		return (*e.baseTypeCache).Items, nil
 	}
 
+	// Drop levels are looked up on poewiki.net.
+	if e.cachePolicy == CachePolicyOffline {
+		return nil, e.offlineError("item")
+	}
+
 	var allBases []model.ItemBase
 	for _, luaFile := range luaFilePaths {
 		dataTable, err := e.luaExecutor.ExecuteScriptAsFunc(luaFile)
@@ -204,10 +240,14 @@ func (e *PathOfBuildingExporter) LoadGeneratedUniques(luaFilePath string) ([]mod
 }
 
 func (e *PathOfBuildingExporter) GetEconomyData(leaguesToRetrieve []string, forceRegenerate bool) (map[string][]EconomyCacheItem, error) {
-	if !forceRegenerate && e.economyCache != nil {
+	if e.economySnapshotPath != "" || (!forceRegenerate && e.economyCache != nil) {
 		return (*e.economyCache).EconomyCacheItems, nil
 	}
 
+	if !e.CanFetchEconomyData() {
+		return nil, e.offlineError("economy")
+	}
+
 	allEconomyData := make(map[string][]EconomyCacheItem)
 
 	categories := map[string]map[string][]string{
@@ -258,11 +298,16 @@ func (e *PathOfBuildingExporter) GetEconomyData(leaguesToRetrieve []string, forc
 
 // SaveItemCache saves the item, essence, gem, and unique data.
 // It checks the item cache's expiry date and only saves if the cache is missing or expired.
+// An expired cache that was used under a lenient cache policy is not saved again, so that it keeps its expiry date.
 func (e *PathOfBuildingExporter) SaveItemCache(items []model.ItemBase, essences []model.Essence, gems []model.Gem, uniques []model.Unique) error {
-	if e.baseTypeCache != nil && time.Now().Before(e.baseTypeCache.ExpiryDate) {
+	if e.baseTypeCache != nil && !e.baseTypeCache.IsExpired() {
 		log.Println("Item cache is still valid. Skipping save.")
 		return nil
 	}
+	if e.baseTypeCache != nil {
+		log.Println("Item cache is expired but was used as is. Skipping save.")
+		return nil
+	}
 
 	log.Println("Item cache is expired or missing. Saving new item cache...")
 	return e.cacheRepo.SaveItemCache(items, essences, gems, uniques)
@@ -270,11 +315,20 @@ func (e *PathOfBuildingExporter) SaveItemCache(items []model.ItemBase, essences
 
 // SaveEconomyCache saves the economy data.
 // It checks the economy cache's expiry date and only saves if the cache is missing or expired.
+// Data read from an economy snapshot or from an expired cache used under a lenient cache policy is never saved.
 func (e *PathOfBuildingExporter) SaveEconomyCache(economy map[string][]EconomyCacheItem, forceSave bool) error {
-	if !forceSave && e.economyCache != nil && time.Now().Before(e.economyCache.ExpiryDate) {
+	if e.economySnapshotPath != "" {
+		log.Println("Economy data was read from a snapshot. Skipping save.")
+		return nil
+	}
+	if !forceSave && e.economyCache != nil && !e.economyCache.IsExpired() {
 		log.Println("Economy cache is still valid. Skipping save.")
 		return nil
 	}
+	if !forceSave && e.economyCache != nil {
+		log.Println("Economy cache is expired but was used as is. Skipping save.")
+		return nil
+	}
 
 	log.Println("Economy cache is expired or missing. Saving new economy cache...")
 	return e.cacheRepo.SaveEconomyCache(economy)
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/data_generation"
+)
+
+const cacheUsage = `usage: ruleforge cache [-config file] [-cache-dir dir] <command>
+
+commands:
+  status              show age, expiry and size of every cache, per league for the economy cache
+  clear [items|economy]
+                      delete the given cache, or both
+  export <file.zip>   write the caches into an archive
+  import <file.zip>   replace the caches with the ones in an archive`
+
+// RunCache implements "ruleforge cache [flags] <command>".
+// It returns the process exit code: 0 on success, 1 on failure and 2 on invalid usage.
+func (a *App) RunCache(args []string) int {
+	flags := flag.NewFlagSet("cache", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprintln(flags.Output(), cacheUsage) }
+	flags.StringVar(&a.configPath, "config", "config.json", "Path to the configuration file. Only read if -cache-dir is not given.")
+	flags.StringVar(&a.cacheDir, "cache-dir", "", "Directory holding the caches. Overrides CacheDir.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	output := os.Stdout
+	a.log = log.New(os.Stderr, "", log.LstdFlags)
+
+	if a.cacheDir == "" {
+		if err := a.loadConfig(); err != nil {
+			log.Printf("cache: %v", err)
+			return 1
+		}
+	}
+	repository := data_generation.NewCacheRepositoryInDir(a.resolveCacheDir())
+
+	command, commandArgs := flags.Arg(0), flags.Args()[1:]
+	var err error
+	switch {
+	case command == "status" && len(commandArgs) == 0:
+		err = a.cacheStatus(output, repository)
+	case command == "clear" && len(commandArgs) <= 1:
+		err = a.cacheClear(output, repository, commandArgs)
+	case command == "export" && len(commandArgs) == 1:
+		err = a.cacheExport(output, repository, commandArgs[0])
+	case command == "import" && len(commandArgs) == 1:
+		err = a.cacheImport(output, repository, commandArgs[0])
+	default:
+		flags.Usage()
+		return 2
+	}
+
+	if err != nil {
+		log.Printf("cache %s: %v", command, err)
+		return 1
+	}
+	return 0
+}
+
+func (a *App) cacheStatus(w io.Writer, repository *data_generation.CacheRepository) error {
+	statuses, err := repository.Status()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "Cache directory: %s\n\n", a.resolveCacheDir())
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CACHE\tENTRIES\tSIZE\tAGE\tEXPIRES")
+	for _, status := range statuses {
+		if !status.Exists {
+			fmt.Fprintf(table, "%s\t-\t-\t-\tmissing (%s)\n", status.Kind, status.Path)
+			continue
+		}
+
+		entries := 0
+		for _, content := range status.Contents {
+			entries += content.Count
+		}
+		fmt.Fprintf(table, "%s\t%d\t%s\t%s\t%s\n", status.Kind, entries, formatSize(status.Size), formatDuration(status.Age()), formatExpiry(status.ExpiryDate))
+
+		for _, content := range status.Contents {
+			fmt.Fprintf(table, "  %s\t%d\t%s\t\t\n", content.Name, content.Count, formatSize(content.Size))
+		}
+	}
+	return table.Flush()
+}
+
+func (a *App) cacheClear(w io.Writer, repository *data_generation.CacheRepository, args []string) error {
+	kinds := data_generation.AllCacheKinds()
+	if len(args) == 1 {
+		kind, err := data_generation.ParseCacheKind(args[0])
+		if err != nil {
+			return err
+		}
+		kinds = []data_generation.CacheKind{kind}
+	}
+
+	removed, err := repository.Clear(kinds...)
+	for _, path := range removed {
+		fmt.Fprintf(w, "Removed %s\n", path)
+	}
+	if err == nil && len(removed) == 0 {
+		fmt.Fprintln(w, "Nothing to clear.")
+	}
+	return err
+}
+
+func (a *App) cacheExport(w io.Writer, repository *data_generation.CacheRepository, archivePath string) error {
+	exported, err := repository.Export(archivePath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Exported %s to %s\n", joinCacheKinds(exported), archivePath)
+	return nil
+}
+
+func (a *App) cacheImport(w io.Writer, repository *data_generation.CacheRepository, archivePath string) error {
+	imported, err := repository.Import(archivePath)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Imported %s into %s\n", joinCacheKinds(imported), a.resolveCacheDir())
+	return nil
+}
+
+func joinCacheKinds(kinds []data_generation.CacheKind) string {
+	names := make([]string, len(kinds))
+	for i, kind := range kinds {
+		names[i] = string(kind)
+	}
+	return strings.Join(names, " and ")
+}
+
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	value, suffix := float64(bytes)/unit, "KiB"
+	for _, next := range []string{"MiB", "GiB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// formatDuration renders a duration in days, hours and minutes, e.g. "3d4h" or "25m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	minutes := (d - hours*time.Hour) / time.Minute
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
+
+func formatExpiry(expiry time.Time) string {
+	date := expiry.Local().Format("2006-01-02 15:04")
+	if remaining := time.Until(expiry); remaining > 0 {
+		return fmt.Sprintf("%s (in %s)", date, formatDuration(remaining))
+	}
+	return fmt.Sprintf("%s (expired %s ago)", date, formatDuration(-time.Until(expiry)))
+}
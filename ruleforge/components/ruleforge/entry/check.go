@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
+)
+
+// Exit codes of the check command.
+const (
+	checkExitOK       = 0
+	checkExitProblems = 1
+	checkExitFailure  = 2
+)
+
+// CheckProblem is a single problem reported by the check command.
+type CheckProblem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// String renders the problem as "file:line:column: message", the format understood by most editors and CI tools.
+func (p CheckProblem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s:%d:%d: %s", p.File, p.Line, p.Column, p.Message)
+}
+
+// RunCheck implements "ruleforge check [flags] [scripts...]".
+// It lexes, parses, resolves imports, validates and checks style references of every script without loading any game data.
+// Without script arguments, all scripts in the configured input directory are checked.
+// It returns the process exit code: 0 when clean, 1 when problems were found and 2 when the check itself could not run.
+func (a *App) RunCheck(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.StringVar(&a.configPath, "config", "config.json", "Path to the configuration file.")
+	format := flags.String("format", "text", "Output format of the report: text or json.")
+	if err := flags.Parse(args); err != nil {
+		return checkExitFailure
+	}
+
+	if *format != "text" && *format != "json" {
+		log.Printf("check: unknown format %q, expected text or json", *format)
+		return checkExitFailure
+	}
+
+	// Progress goes to stderr; stdout is kept for the report alone.
+	report := os.Stdout
+	a.log = log.New(os.Stderr, "", log.LstdFlags)
+
+	problems, err := a.check(flags.Args())
+	if err != nil {
+		log.Printf("check: %s", diagnostics.Render(err))
+		return checkExitFailure
+	}
+
+	if err := writeCheckReport(report, *format, problems); err != nil {
+		log.Printf("check: writing report: %v", err)
+		return checkExitFailure
+	}
+
+	if len(problems) > 0 {
+		return checkExitProblems
+	}
+	return checkExitOK
+}
+
+// check runs every check on the given scripts, or on all scripts in the input directory if none are given.
+func (a *App) check(scripts []string) ([]CheckProblem, error) {
+	if err := a.loadConfig(); err != nil {
+		return nil, err
+	}
+
+	if len(scripts) == 0 {
+		var err error
+		scripts, err = listFilesWithExtension(a.config.RuleforgeInputDir, ".rf")
+		if err != nil {
+			return nil, fmt.Errorf("could not list Ruleforge scripts in %s: %w", a.config.RuleforgeInputDir, err)
+		}
+	}
+
+	cssVariables, err := a.loadCSSVariables()
+	if err != nil {
+		return nil, err
+	}
+
+	baseTypeData, err := a.loadBaseTypeData()
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]CheckProblem, 0)
+	for _, script := range scripts {
+		a.log.Printf("Checking: %s", script)
+		if err := a.checkScript(script, cssVariables, *baseTypeData); err != nil {
+			problems = append(problems, problemsFromError(script, err)...)
+		}
+	}
+	return problems, nil
+}
+
+// checkScript runs the checks of a single script and returns the first stage that failed.
+// Later stages are skipped, as they would only repeat the earlier problems.
+func (a *App) checkScript(path string, cssVariables map[string]string, baseTypeData []config.BaseTypeAutomationEntry) error {
+	tree, err := a.parseAndValidate(path)
+	if err != nil {
+		return err
+	}
+
+	return compilation.CheckStyleReferences(tree, a.config.StyleJSONFile, cssVariables, baseTypeData)
+}
+
+// problemsFromError splits an error into one problem per diagnostic it carries.
+func problemsFromError(script string, err error) []CheckProblem {
+	var list diagnostics.List
+	if errors.As(err, &list) {
+		problems := make([]CheckProblem, 0, len(list))
+		for _, diagnostic := range list {
+			problems = append(problems, problemFromDiagnostic(script, diagnostic))
+		}
+		return problems
+	}
+
+	var diagnostic *diagnostics.Diagnostic
+	if errors.As(err, &diagnostic) {
+		return []CheckProblem{problemFromDiagnostic(script, diagnostic)}
+	}
+
+	return []CheckProblem{{File: script, Message: err.Error()}}
+}
+
+func problemFromDiagnostic(script string, diagnostic *diagnostics.Diagnostic) CheckProblem {
+	file := diagnostic.Position.File
+	if file == "" {
+		file = script
+	}
+
+	return CheckProblem{
+		File:    file,
+		Line:    diagnostic.Position.Line,
+		Column:  diagnostic.Position.Column,
+		Message: diagnostic.Err.Error(),
+	}
+}
+
+func writeCheckReport(w io.Writer, format string, problems []CheckProblem) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(problems)
+	}
+
+	for _, problem := range problems {
+		if _, err := fmt.Fprintln(w, problem); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -6,17 +6,18 @@ import (
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"log"
 	"os"
-	"path"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 
-	common_compiler "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/postprocessor"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/imports"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/data_generation"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/data_generation/model"
-	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/validation"
 )
@@ -28,11 +29,18 @@ type App struct {
 	verbose         bool
 	updateCacheOnly bool
 	forceSaveCache  bool
+	watch           bool
+	jobs            int
+	offline         bool
+	economySnapshot string
+	cacheDir        string
+	defines         defineFlags
 
 	// Core Components
 	log      *log.Logger
 	config   *config.ConfigurationModel
 	exporter *data_generation.PathOfBuildingExporter
+	imports  *imports.Resolver
 
 	// Loaded Data
 	baseTypes   []string
@@ -45,19 +53,41 @@ type App struct {
 
 func main() {
 	app := &App{
-		log: log.New(os.Stdout, "", log.LstdFlags),
+		log:     log.New(os.Stdout, "", log.LstdFlags),
+		defines: defineFlags{},
 	}
 
 	// Define command-line flags. The exporter handles caching automatically.
-	// To force a refresh, the user can delete the './cache' directory.
+	// To force a refresh, use "ruleforge cache clear".
 	flag.StringVar(&app.configPath, "config", "config.json", "Path to the configuration file.")
 	flag.BoolVar(&app.verbose, "verbose", false, "Enable verbose output for debugging.")
 	flag.BoolVar(&app.updateCacheOnly, "update-cache-only", false, "Fetch/update data and save to cache without running compilation.")
+	flag.BoolVar(&app.watch, "watch", false, "Keep running and recompile affected scripts when scripts, imports, styles, CSS or CSV files change.")
+	flag.BoolVar(&app.offline, "offline", false, "Never contact the network; use the caches even if they have expired. Overrides EconomyCachePolicy.")
+	flag.StringVar(&app.cacheDir, "cache-dir", "", "Directory holding the item and economy caches. Overrides CacheDir.")
+	flag.StringVar(&app.economySnapshot, "economy-snapshot", "", "Economy cache file to use instead of the economy cache. Overrides EconomySnapshotFile.")
+	flag.IntVar(&app.jobs, "jobs", runtime.NumCPU(), "Maximum number of scripts compiled at the same time. Verbose mode always compiles one at a time.")
+	flag.Var(app.defines, "D", "Define name=value for the conditions of IF blocks, read as $name. Can be repeated.")
+	if len(os.Args) > 1 && os.Args[1] == "check" {
+		os.Exit(app.RunCheck(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "cache" {
+		os.Exit(app.RunCache(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "simulate" {
+		os.Exit(app.RunSimulate(os.Args[2:]))
+	}
+
 	flag.Parse()
 
+	// Parse trees are printed straight to stdout, so they would interleave between workers.
+	if app.verbose {
+		app.jobs = 1
+	}
+
 	if err := app.Run(); err != nil {
 		log.Println("If you struggle to understand the error, you can contact the developer on Discord (mr.hoornasp.learningexpert) or through e-mail: md.career@protonmail.com")
-		log.Fatalf("fatal: %v", err)
+		log.Fatalf("fatal: %s", diagnostics.Render(err))
 	}
 }
 
@@ -68,30 +98,21 @@ func (a *App) Run() error {
 		return err
 	}
 
-	cssParser, err := config.NewCSSParserFromFile(a.config.StyleColorCSSFile)
-
+	props, err := a.loadCSSVariables()
 	if err != nil {
-		return fmt.Errorf("NewCSSParserFromFile: %v", err)
-	}
-
-	props, err := cssParser.Parse()
-
-	if err != nil {
-		return fmt.Errorf("parse: %v", err)
+		return err
 	}
 
-	// Initialize the exporter, which will attempt to load from cache.
-	a.log.Println("Initializing data exporter (will use cache if available and valid)...")
-	a.exporter = data_generation.NewPathOfBuildingExporter()
-
-	// Fetch data. The exporter will return cached data or re-parse files as needed.
-	if err := a.fetchData(); err != nil {
-		return fmt.Errorf("could not fetch data: %w", err)
+	// Validate the scripts first, so mistakes are reported before any game data is fetched.
+	// In watch mode, failures are reported per rebuild instead.
+	if !a.updateCacheOnly && !a.watch {
+		if err := a.validateScripts(); err != nil {
+			return err
+		}
 	}
 
-	// Save data back to cache. The exporter will skip if the cache is still valid.
-	if err := a.saveCaches(); err != nil {
-		return fmt.Errorf("could not save data to cache: %w", err)
+	if err := a.loadGameData(); err != nil {
+		return err
 	}
 
 	// If the user only wants to update the cache, we're done.
@@ -103,8 +124,18 @@ func (a *App) Run() error {
 	// Prepare shared data for compilation.
 	a.prepareBaseTypes()
 
+	data, err := a.loadCompilationData(props)
+	if err != nil {
+		return err
+	}
+
+	// In watch mode, failures are reported per rebuild instead of ending the run.
+	if a.watch {
+		return a.watchAndRecompile(data)
+	}
+
 	// Compile Ruleforge scripts.
-	if err := a.compileRules(props); err != nil {
+	if err := a.compileRules(data); err != nil {
 		return fmt.Errorf("error during rule compilation: %w", err)
 	}
 
@@ -112,6 +143,28 @@ func (a *App) Run() error {
 	return nil
 }
 
+// loadGameData loads the item and economy data through the exporter and refreshes the caches.
+func (a *App) loadGameData() error {
+	// Initialize the exporter, which will attempt to load from cache.
+	a.log.Println("Initializing data exporter (will use cache if available and valid)...")
+	exporter, err := data_generation.NewPathOfBuildingExporterWithOptions(a.exporterOptions())
+	if err != nil {
+		return err
+	}
+	a.exporter = exporter
+
+	// Fetch data. The exporter will return cached data or re-parse files as needed.
+	if err := a.fetchData(); err != nil {
+		return fmt.Errorf("could not fetch data: %w", err)
+	}
+
+	// Save data back to cache. The exporter will skip if the cache is still valid.
+	if err := a.saveCaches(); err != nil {
+		return fmt.Errorf("could not save data to cache: %w", err)
+	}
+	return nil
+}
+
 // loadConfig loads and validates the JSON configuration file.
 func (a *App) loadConfig() error {
 	loader := config.NewConfigurationLoader()
@@ -119,15 +172,58 @@ func (a *App) loadConfig() error {
 	if err != nil {
 		return fmt.Errorf("could not load configuration from %s: %w", a.configPath, err)
 	}
+	a.log.Println(configuration.String())
 
 	if err := configuration.Validate(); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
 	a.config = configuration
+	a.imports = imports.NewResolverWithOptions(imports.ResolverOptions{SearchPaths: configuration.GetImportSearchPaths()})
 	a.log.Println("Configuration loaded and validated successfully.")
 	return nil
 }
 
+// exporterOptions combines the cache settings of the configuration with the command-line overrides.
+func (a *App) exporterOptions() data_generation.ExporterOptions {
+	options := data_generation.ExporterOptions{
+		CacheDir:            a.resolveCacheDir(),
+		CachePolicy:         data_generation.CachePolicy(a.config.GetEconomyCachePolicy()),
+		EconomySnapshotPath: a.config.EconomySnapshotFile,
+	}
+	if a.offline {
+		options.CachePolicy = data_generation.CachePolicyOffline
+	}
+	if a.economySnapshot != "" {
+		options.EconomySnapshotPath = a.economySnapshot
+	}
+	return options
+}
+
+// resolveCacheDir returns the -cache-dir flag if given, otherwise the configured cache directory.
+func (a *App) resolveCacheDir() string {
+	if a.cacheDir != "" {
+		return a.cacheDir
+	}
+	return a.config.GetCacheDir()
+}
+
+// loadCSSVariables parses the style color CSS file into its variables.
+func (a *App) loadCSSVariables() (map[string]string, error) {
+	cssParser, err := config.NewCSSParserFromFile(a.config.StyleColorCSSFile)
+
+	if err != nil {
+		return nil, fmt.Errorf("NewCSSParserFromFile: %v", err)
+	}
+
+	props, err := cssParser.Parse()
+
+	if err != nil {
+		return nil, fmt.Errorf("parse: %v", err)
+	}
+
+	return props, nil
+}
+
 // fetchData uses the exporter to load all required data.
 // The exporter's methods will internally decide whether to use cached data or re-fetch from source.
 func (a *App) fetchData() error {
@@ -177,6 +273,10 @@ func (a *App) fetchEconomyData(regenerate bool) error {
 			}
 		}
 		if invalid {
+			// Offline or with a snapshot, missing data cannot be fetched; regenerating would only fail.
+			if !a.exporter.CanFetchEconomyData() {
+				return fmt.Errorf("the cached economy data is incomplete and cannot be fetched again under the current cache policy")
+			}
 			return a.fetchEconomyData(true)
 		}
 
@@ -220,7 +320,8 @@ func (a *App) prepareBaseTypes() {
 }
 
 // compileRules finds and processes all Ruleforge scripts.
-func (a *App) compileRules(cssVariables map[string]string) error {
+// It returns the error of the first failed script in script order.
+func (a *App) compileRules(data *compilationData) error {
 	ruleforgeScripts, err := listFilesWithExtension(a.config.RuleforgeInputDir, ".rf")
 	if err != nil {
 		return fmt.Errorf("could not list Ruleforge scripts in %s: %w", a.config.RuleforgeInputDir, err)
@@ -232,88 +333,101 @@ func (a *App) compileRules(cssVariables map[string]string) error {
 	}
 	a.log.Printf("Found %d Ruleforge scripts to process...", len(ruleforgeScripts))
 
-	for _, scriptPath := range ruleforgeScripts {
-		if err := a.processRuleforgeScript(scriptPath, cssVariables); err != nil {
-			return fmt.Errorf("failed to process script %s: %w", scriptPath, err)
+	for _, result := range a.compileScripts(ruleforgeScripts, data) {
+		if result.err != nil {
+			return fmt.Errorf("failed to process script %s: %w", result.path, result.err)
 		}
 	}
 	return nil
 }
 
-func (a *App) processRuleforgeScript(path string, cssVariables map[string]string) error {
-	a.log.Printf("Processing: %s", path)
-
-	file, err := a.openScript(path)
+// validateScripts lexes, parses and validates every script in the input directory without compiling it.
+func (a *App) validateScripts() error {
+	ruleforgeScripts, err := listFilesWithExtension(a.config.RuleforgeInputDir, ".rf")
 	if err != nil {
-		return err
+		return fmt.Errorf("could not list Ruleforge scripts in %s: %w", a.config.RuleforgeInputDir, err)
 	}
-	defer file.Close()
 
-	tree, err := a.lexAndParse(file)
-	if err != nil {
-		return err
+	for _, path := range ruleforgeScripts {
+		if _, err := a.parseAndValidate(path); err != nil {
+			return fmt.Errorf("failed to process script %s: %w", path, err)
+		}
 	}
+	return nil
+}
 
-	tree = a.postProcess(tree)
-	if a.verbose {
-		a.logParseTree(tree, path)
+// parseAndValidate returns the validated parse tree of the script.
+func (a *App) parseAndValidate(path string) (*shared.ParseTree[symbols.LexingTokenType], error) {
+	tree, _, err := a.imports.Resolve(path)
+	if err != nil {
+		return nil, err
+	}
+	tree, err = a.postProcess(tree)
+	if err != nil {
+		return nil, err
 	}
-
 	if err := a.validateTree(tree); err != nil {
-		return err
+		return nil, err
 	}
+	return tree, nil
+}
 
-	baseTypeData, err := a.loadBaseTypeData()
-	if err != nil {
-		return err
-	}
+// processRuleforgeScript compiles a single script and writes its filters. Modules are only validated.
+// It returns the files the script was read from, i.e. the script and everything it imports, even if compilation failed.
+func (a *App) processRuleforgeScript(path string, data *compilationData) ([]string, error) {
+	a.log.Printf("Processing: %s", path)
+	dependencies := []string{path}
 
-	lines, name, err := a.compileTree(tree, *baseTypeData, cssVariables)
+	tree, imported, err := a.imports.Resolve(path)
+	dependencies = append(dependencies, imported...)
 	if err != nil {
-		return err
+		return dependencies, err
 	}
 
-	return a.writeOutputs(lines, name)
-}
-
-func (a *App) openScript(path string) (*os.File, error) {
-	file, err := os.Open(path)
+	tree, err = a.postProcess(tree)
 	if err != nil {
-		return nil, fmt.Errorf("opening file %s: %w", path, err)
+		return dependencies, err
+	}
+	if a.verbose {
+		a.logParseTree(tree, path)
 	}
-	return file, nil
-}
 
-func (a *App) lexAndParse(file *os.File) (*shared.ParseTree[symbols.LexingTokenType], error) {
-	handler := common_compiler.NewFileHandler(
-		file,
-		rules.GetLexingRules(),
-		rules.GetParsingRules(),
-		symbols.IgnoreToken,
-	)
-	if _, err := handler.Lex(); err != nil {
-		return nil, fmt.Errorf("lexing failed: %w", err)
+	if err := a.validateTree(tree); err != nil {
+		return dependencies, err
 	}
-	tree, err := handler.Parse()
-	if err != nil {
-		return nil, fmt.Errorf("parsing failed: %w", err)
+	if imports.IsModule(tree) {
+		a.log.Printf("Validated module %s, it is compiled as part of the scripts that import it.", path)
+		return dependencies, nil
 	}
-	resolvedImportsTree, err := a.ResolveImports(tree)
 
+	filters, name, err := a.compileTree(tree, data)
 	if err != nil {
-		return nil, fmt.Errorf("resolving imports failed: %w", err)
+		return dependencies, err
 	}
+	a.reportShadowedRules(filters)
 
-	return resolvedImportsTree, nil
+	return dependencies, a.writeOutputs(filters, name)
 }
 
-func (a *App) postProcess(tree *shared.ParseTree[symbols.LexingTokenType]) *shared.ParseTree[symbols.LexingTokenType] {
+// postProcess strips the tree of syntax-only nodes, unrolls loops and expands the macros defined in the script.
+func (a *App) postProcess(tree *shared.ParseTree[symbols.LexingTokenType]) (*shared.ParseTree[symbols.LexingTokenType], error) {
 	pp := postprocessor.PostProcessor[symbols.LexingTokenType]{}
 	tree = pp.FilterOutSymbols([]string{
 		symbols.ParseSymbolWhitespace.String(),
 		symbols.ParseSymbolBlockOperator.String(),
 	}, tree)
-	return pp.RemoveEmptyNodes(tree)
+	tree = pp.RemoveEmptyNodes(tree)
+
+	tree, err := compilation.UnrollLoops(tree)
+	if err != nil {
+		return nil, fmt.Errorf("unrolling loops failed: %w", err)
+	}
+
+	expanded, err := compilation.ExpandMacros(tree)
+	if err != nil {
+		return nil, fmt.Errorf("expanding macros failed: %w", err)
+	}
+	return expanded, nil
 }
 
 func (a *App) logParseTree(tree *shared.ParseTree[symbols.LexingTokenType], path string) {
@@ -322,7 +436,15 @@ func (a *App) logParseTree(tree *shared.ParseTree[symbols.LexingTokenType], path
 }
 
 func (a *App) validateTree(tree *shared.ParseTree[symbols.LexingTokenType]) error {
-	if err := validation.NewParseTreeValidator(tree).Validate(); err != nil {
+	variables, err := compilation.NewTreeWalker(tree).ExtractVariables()
+	if err != nil {
+		return fmt.Errorf("parse tree validation failed: %w", err)
+	}
+	options := validation.ParseTreeValidatorOptions{
+		Variables:          variables,
+		EvaluateExpression: compilation.EvaluateConditionExpression,
+	}
+	if err := validation.NewParseTreeValidatorWithOptions(tree, options).Validate(); err != nil {
 		return fmt.Errorf("parse tree validation failed: %w", err)
 	}
 	return nil
@@ -339,13 +461,15 @@ func (a *App) loadBaseTypeData() (*[]config.BaseTypeAutomationEntry, error) {
 
 func (a *App) compileTree(
 	tree *shared.ParseTree[symbols.LexingTokenType],
-	baseTypeData []config.BaseTypeAutomationEntry,
-	cssVariables map[string]string,
-) ([]string, string, error) {
+	data *compilationData,
+) ([]compilation.CompiledFilter, string, error) {
 	compiler, err := compilation.NewCompiler(
 		tree,
 		compilation.CompilerConfiguration{
 			StyleJsonPath: a.config.StyleJSONFile,
+			Styles:        data.styles,
+			Defines:       a.defines,
+			Logger:        a.log,
 		},
 		a.baseTypes,
 		a.itemBases,
@@ -354,29 +478,31 @@ func (a *App) compileTree(
 		a.config.GetLeagueWeights(),
 		a.config.EconomyNormalizationStrategy,
 		a.config.ChaseVSGeneralPotentialFactor,
-		baseTypeData,
-		cssVariables,
+		data.baseTypeData,
+		data.cssVariables,
 		a.config.CustomEquipmentPresets,
 	)
 	if err != nil {
 		return nil, "", fmt.Errorf("compiler initialization failed: %w", err)
 	}
-	lines, err, name := compiler.CompileIntoFilter()
+	filters, err, name := compiler.CompileIntoFilter()
 
 	if err != nil {
 		return nil, "", fmt.Errorf("compile failed: %w", err)
 	}
 
-	return lines, name, nil
+	return filters, name, nil
 }
 
-func (a *App) writeOutputs(lines []string, name string) error {
+func (a *App) writeOutputs(filters []compilation.CompiledFilter, name string) error {
 	for _, dir := range a.config.FilterOutputDirs {
-		path := filepath.Join(dir, name+".filter")
-		if err := writeLines(lines, path); err != nil {
-			return fmt.Errorf("writing output file %s failed: %w", path, err)
+		for _, filter := range filters {
+			path := filepath.Join(dir, filter.FileName(name))
+			if err := writeLines(filter.Lines, path); err != nil {
+				return fmt.Errorf("writing output file %s failed: %w", path, err)
+			}
+			a.log.Printf("Successfully wrote filter to %s", path)
 		}
-		a.log.Printf("Successfully wrote filter to %s", path)
 	}
 	return nil
 }
@@ -436,65 +562,28 @@ func (a *App) extractUniqueBases(pobDataPath string) ([]model.Unique, error) {
 	return allUniques, nil
 }
 
-// --- Imports ---
-
-//goland:noinspection t
-func (a *App) ResolveImports(node *shared.ParseTree[symbols.LexingTokenType]) (*shared.ParseTree[symbols.LexingTokenType], error) {
-	if node.Symbol != symbols.ParseSymbolImport.String() && len(node.Children) == 0 {
-		return node, nil
-	} else if node.Symbol != symbols.ParseSymbolImport.String() && len(node.Children) > 0 {
-		resolvedNode := &shared.ParseTree[symbols.LexingTokenType]{
-			Symbol:   node.Symbol,
-			Token:    node.Token,
-			Children: make([]*shared.ParseTree[symbols.LexingTokenType], 0),
-		}
-
-		for _, child := range node.Children {
-			resolvedChild, err := a.ResolveImports(child)
+// --- Defines ---
 
-			if err != nil {
-				return nil, err
-			}
+// defineFlags collects the repeated `-D name=value` flags.
+type defineFlags map[string]string
 
-			resolvedNode.Children = append(resolvedNode.Children, resolvedChild)
-		}
-
-		return resolvedNode, nil
+func (d defineFlags) String() string {
+	pairs := make([]string, 0, len(d))
+	for name, value := range d {
+		pairs = append(pairs, name+"="+value)
 	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
 
-	if node.Symbol == symbols.ParseSymbolImport.String() {
-		importFileNameNode := node.FindSymbolNode(symbols.ParseSymbolValue.String())
-		importFileName := importFileNameNode.Token.ValueToString()
-
-		importFilePath := path.Join(a.config.RuleforgeInputDir, importFileName)
-
-		file, err := a.openScript(importFilePath)
-		if err != nil {
-			return nil, err
-		}
-		defer file.Close()
-
-		handler := common_compiler.NewFileHandler(
-			file,
-			rules.GetLexingRules(),
-			rules.GetParsingRules(),
-			symbols.IgnoreToken,
-		)
-
-		_, err = handler.Lex()
-		if err != nil {
-			return nil, err
-		}
-
-		parsed, err := handler.Parse()
-		if err != nil {
-			return nil, err
-		}
-
-		return a.ResolveImports(parsed)
+func (d defineFlags) Set(flagValue string) error {
+	name, value, ok := strings.Cut(flagValue, "=")
+	name = strings.TrimPrefix(name, "$")
+	if !ok || name == "" {
+		return fmt.Errorf("expected name=value, got %q", flagValue)
 	}
-
-	return nil, fmt.Errorf("something went wrong when importing symbol '%s'", node.Symbol)
+	d[name] = value
+	return nil
 }
 
 // --- File I/O Helpers ---
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/config"
+)
+
+// compilationData is the data every script is compiled against.
+// It is loaded once and only read during compilation, so all workers share it.
+type compilationData struct {
+	cssVariables map[string]string
+	styles       map[string]config.Style
+	baseTypeData []config.BaseTypeAutomationEntry
+}
+
+// loadCompilationData loads the styles and the base type automation entries for the given CSS variables.
+func (a *App) loadCompilationData(cssVariables map[string]string) (*compilationData, error) {
+	styles, err := config.LoadStyles(a.config.StyleJSONFile, cssVariables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load styles: %w", err)
+	}
+
+	baseTypeData, err := a.loadBaseTypeData()
+	if err != nil {
+		return nil, err
+	}
+
+	return &compilationData{
+		cssVariables: cssVariables,
+		styles:       styles,
+		baseTypeData: *baseTypeData,
+	}, nil
+}
+
+// scriptResult is the outcome of compiling one script.
+type scriptResult struct {
+	path         string
+	dependencies []string
+	err          error
+}
+
+// compileScripts compiles the scripts on at most a.jobs workers and returns their results in script order.
+// Each script logs into its own buffer, which is written out once the script and all scripts before it are done,
+// so the log reads the same as a sequential run no matter how the work was scheduled.
+func (a *App) compileScripts(scripts []string, data *compilationData) []scriptResult {
+	results := make([]scriptResult, len(scripts))
+	logs := make([]bytes.Buffer, len(scripts))
+	done := make([]chan struct{}, len(scripts))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	indices := make(chan int)
+	go func() {
+		for i := range scripts {
+			indices <- i
+		}
+		close(indices)
+	}()
+
+	for range max(1, min(a.jobs, len(scripts))) {
+		go func() {
+			for i := range indices {
+				worker := *a
+				worker.log = log.New(&logs[i], a.log.Prefix(), a.log.Flags())
+
+				dependencies, err := worker.processRuleforgeScript(scripts[i], data)
+				results[i] = scriptResult{path: scripts[i], dependencies: dependencies, err: err}
+				close(done[i])
+			}
+		}()
+	}
+
+	for i := range scripts {
+		<-done[i]
+		if _, err := a.log.Writer().Write(logs[i].Bytes()); err != nil {
+			a.log.Printf("could not write the log of %s: %v", scripts[i], err)
+		}
+	}
+	return results
+}
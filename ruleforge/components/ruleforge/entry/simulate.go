@@ -0,0 +1,212 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/imports"
+	model2 "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/model"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/compilation/simulation"
+)
+
+const simulateUsage = `usage: ruleforge simulate [flags] <script.rf> <item>
+
+Compiles the script without writing any filter and shows which blocks catch the item.
+The item is a file holding a JSON object or the text the game copies with Ctrl+C
+(preferably Ctrl+Alt+C, which includes the mod names); use - to read it from stdin.
+
+flags:`
+
+// RunSimulate implements "ruleforge simulate [flags] <script> <item>".
+// It returns the process exit code: 0 on success, 1 on failure and 2 on invalid usage.
+func (a *App) RunSimulate(args []string) int {
+	flags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), simulateUsage)
+		flags.PrintDefaults()
+	}
+	flags.StringVar(&a.configPath, "config", "config.json", "Path to the configuration file.")
+	flags.BoolVar(&a.offline, "offline", false, "Never contact the network; use the caches even if they have expired.")
+	flags.StringVar(&a.cacheDir, "cache-dir", "", "Directory holding the item and economy caches. Overrides CacheDir.")
+	flags.StringVar(&a.economySnapshot, "economy-snapshot", "", "Economy cache file to use instead of the economy cache.")
+	strictness := flags.String("strictness", "", "Strictness of the filter to test, e.g. SEMI-STRICT. Defaults to the script's STRICTNESS.")
+	areaLevel := flags.Int("area-level", 0, "Area level the item dropped in. Overrides the item's AreaLevel.")
+	flags.Var(a.defines, "D", "Define name=value for the conditions of IF blocks, read as $name. Can be repeated.")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+
+	output := os.Stdout
+	a.log = log.New(os.Stderr, "", log.LstdFlags)
+
+	item, err := readSimulatedItem(flags.Arg(1))
+	if err != nil {
+		log.Printf("simulate: %v", err)
+		return 1
+	}
+	if *areaLevel != 0 {
+		item.AreaLevel = *areaLevel
+	}
+
+	filter, name, err := a.compileForSimulation(flags.Arg(0), *strictness)
+	if err != nil {
+		log.Printf("simulate: %s", diagnostics.Render(err))
+		return 1
+	}
+
+	result, err := simulation.Simulate(*filter, item)
+	if err != nil {
+		log.Printf("simulate: %v", err)
+		return 1
+	}
+	writeSimulationReport(output, filter.FileName(name), item, result)
+	return 0
+}
+
+func readSimulatedItem(path string) (simulation.Item, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return simulation.Item{}, fmt.Errorf("reading item: %w", err)
+	}
+	return simulation.ParseItem(data)
+}
+
+// compileForSimulation compiles the script in memory and returns the filter of the requested strictness.
+// Without a strictness, the one in the script's metadata is used.
+func (a *App) compileForSimulation(path, strictness string) (*compilation.CompiledFilter, string, error) {
+	if err := a.loadConfig(); err != nil {
+		return nil, "", err
+	}
+
+	// The script is validated before any game data is fetched.
+	tree, err := a.parseAndValidate(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if imports.IsModule(tree) {
+		return nil, "", fmt.Errorf("%s is a module, simulate a script that imports it instead", path)
+	}
+
+	if strictness == "" {
+		strictness = compilation.NewTreeWalker(tree).ExtractMetadata().Strictness
+	}
+	level, err := model2.ParseStrictness(strictness)
+	if err != nil {
+		return nil, "", err
+	}
+
+	props, err := a.loadCSSVariables()
+	if err != nil {
+		return nil, "", err
+	}
+	if err := a.loadGameData(); err != nil {
+		return nil, "", err
+	}
+	a.prepareBaseTypes()
+
+	data, err := a.loadCompilationData(props)
+	if err != nil {
+		return nil, "", err
+	}
+
+	filters, name, err := a.compileTree(tree, data)
+	if err != nil {
+		return nil, "", err
+	}
+	for i := range filters {
+		if filters[i].Strictness == level {
+			return &filters[i], name, nil
+		}
+	}
+	return nil, "", fmt.Errorf("the script produced no %s filter", level)
+}
+
+func writeSimulationReport(w io.Writer, filterName string, item simulation.Item, result *simulation.Result) {
+	fmt.Fprintf(w, "Item:   %s %s (%s), item level %d, area level %d\n", item.Rarity, item.BaseType, item.Class, item.ItemLevel, item.AreaLevel)
+	fmt.Fprintf(w, "Filter: %s\n\n", filterName)
+
+	if len(result.Matches) == 0 {
+		fmt.Fprintln(w, "No block matches the item; the game shows it without styling.")
+	}
+	for _, match := range result.Matches {
+		fmt.Fprintf(w, "Line %d: %s\n", match.Block.Line, describeOrigin(match.Origin))
+		for _, line := range match.Block.Lines {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(result.Matches) > 1 {
+		fmt.Fprintln(w, "Effective style:")
+		for _, line := range result.Style {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if result.Visible {
+		fmt.Fprintln(w, "Result: SHOWN")
+	} else {
+		fmt.Fprintln(w, "Result: HIDDEN")
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(w, "WARNING: %s\n", warning)
+	}
+}
+
+// describeOrigin renders where a block came from, e.g. `section "Currency", rule at main.rf:12:5, style $currency`.
+func describeOrigin(origin *compilation.BlockOrigin) string {
+	if origin == nil {
+		return "unknown origin"
+	}
+
+	parts := []string{fmt.Sprintf("section %q", origin.Section)}
+	if origin.Position.IsValid() {
+		parts = append(parts, fmt.Sprintf("%s at %s", origin.Source, origin.Position))
+	} else {
+		parts = append(parts, origin.Source)
+	}
+	if origin.Style != "" {
+		parts = append(parts, "style "+origin.Style)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reportShadowedRules warns about rules of the script that can never match, because an earlier block catches all their items.
+// Each rule is reported once, for the first filter it is shadowed in.
+func (a *App) reportShadowedRules(filters []compilation.CompiledFilter) {
+	reported := make(map[string]bool)
+	for _, filter := range filters {
+		shadowings, err := simulation.FindShadowedBlocks(filter)
+		if err != nil {
+			a.log.Printf("WARNING: could not check the %s filter for shadowed rules: %v", filter.Strictness, err)
+			continue
+		}
+
+		for _, shadowing := range shadowings {
+			origin := shadowing.Shadowed.Origin
+			if origin == nil || origin.Source != "rule" || reported[origin.Position.String()] {
+				continue
+			}
+			reported[origin.Position.String()] = true
+			a.log.Printf("WARNING: %s can never match in the %s filter: the block at line %d (%s) catches all its items first",
+				describeOrigin(origin), filter.Strictness, shadowing.By.Block.Line, describeOrigin(shadowing.By.Origin))
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+)
+
+const (
+	// watchPollInterval is how often the watched files are checked for changes.
+	watchPollInterval = 250 * time.Millisecond
+	// watchDebounce is how long the files must stay unchanged before a rebuild starts,
+	// so that an editor saving several files at once triggers a single rebuild.
+	watchDebounce = 500 * time.Millisecond
+)
+
+// fileState is what the watcher remembers of a file to detect changes.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+func (s fileState) equal(other fileState) bool {
+	return s.exists == other.exists && s.modTime.Equal(other.modTime) && s.size == other.size
+}
+
+// watchAndRecompile compiles every script and then keeps recompiling the scripts affected by each change.
+// Item bases, economy data and base types are loaded once by Run and reused for every rebuild.
+// Styles, CSS variables and base type automation entries are reloaded when their files change.
+// It only returns if the input directory can no longer be read.
+func (a *App) watchAndRecompile(data *compilationData) error {
+	scripts, err := listFilesWithExtension(a.config.RuleforgeInputDir, ".rf")
+	if err != nil {
+		return fmt.Errorf("could not list Ruleforge scripts in %s: %w", a.config.RuleforgeInputDir, err)
+	}
+
+	dependencies := make(map[string][]string)
+	a.rebuild(0, nil, scripts, data, dependencies)
+
+	a.log.Printf("Watching %s, its imports, %s, %s and %s for changes (Ctrl+C to stop)...",
+		a.config.RuleforgeInputDir, a.config.StyleJSONFile, a.config.StyleColorCSSFile, a.config.BaseTypeCSVFile)
+
+	snapshot := a.snapshotWatchedFiles(dependencies)
+	pending := make(map[string]bool)
+	var lastChange time.Time
+	rebuildNumber := 0
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current := a.snapshotWatchedFiles(dependencies)
+		if changed := changedFiles(snapshot, current); len(changed) > 0 {
+			for _, file := range changed {
+				pending[file] = true
+			}
+			snapshot = current
+			lastChange = time.Now()
+			continue
+		}
+
+		if len(pending) == 0 || time.Since(lastChange) < watchDebounce {
+			continue
+		}
+
+		changed := make([]string, 0, len(pending))
+		for file := range pending {
+			changed = append(changed, file)
+		}
+		slices.Sort(changed)
+		pending = make(map[string]bool)
+
+		if a.changesSharedFiles(changed) {
+			reloaded, err := a.reloadCompilationData(data, changed)
+			if err != nil {
+				a.log.Printf("Rebuild skipped: %v", err)
+				continue
+			}
+			data = reloaded
+		}
+
+		scripts, err := listFilesWithExtension(a.config.RuleforgeInputDir, ".rf")
+		if err != nil {
+			return fmt.Errorf("could not list Ruleforge scripts in %s: %w", a.config.RuleforgeInputDir, err)
+		}
+
+		// Forget deleted scripts, so their imports are no longer watched.
+		for script := range dependencies {
+			if !slices.Contains(cleanPaths(scripts), script) {
+				delete(dependencies, script)
+			}
+		}
+
+		rebuildNumber++
+		a.rebuild(rebuildNumber, changed, a.affectedScripts(scripts, changed, dependencies), data, dependencies)
+
+		// Start watching newly imported files, but keep the old state of known files,
+		// so that changes made during the rebuild still trigger the next one.
+		for file, state := range a.snapshotWatchedFiles(dependencies) {
+			if _, known := snapshot[file]; !known {
+				snapshot[file] = state
+			}
+		}
+	}
+	return nil
+}
+
+// rebuild compiles the given scripts, records their dependencies and prints a summary.
+// Rebuild 0 is the initial build.
+func (a *App) rebuild(number int, changed []string, scripts []string, data *compilationData, dependencies map[string][]string) {
+	start := time.Now()
+
+	if number == 0 {
+		a.log.Printf("=== Initial build: %d scripts ===", len(scripts))
+	} else {
+		a.log.Printf("=== Rebuild #%d: %d changed (%s), %d affected scripts ===", number, len(changed), strings.Join(baseNames(changed), ", "), len(scripts))
+	}
+
+	results := a.compileScripts(scripts, data)
+
+	failed := 0
+	for _, result := range results {
+		dependencies[filepath.Clean(result.path)] = cleanPaths(result.dependencies)
+		if result.err != nil {
+			failed++
+			a.log.Printf("  FAILED %s: %s", filepath.Base(result.path), diagnostics.Render(result.err))
+		} else {
+			a.log.Printf("  ok     %s", filepath.Base(result.path))
+		}
+	}
+
+	a.log.Printf("=== %d succeeded, %d failed in %s ===", len(results)-failed, failed, time.Since(start).Round(time.Millisecond))
+}
+
+// changesSharedFiles reports whether the style, CSS or CSV file is among the changed files.
+func (a *App) changesSharedFiles(changed []string) bool {
+	shared := []string{
+		filepath.Clean(a.config.StyleJSONFile),
+		filepath.Clean(a.config.StyleColorCSSFile),
+		filepath.Clean(a.config.BaseTypeCSVFile),
+	}
+	for _, file := range changed {
+		if slices.Contains(shared, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadCompilationData loads the styles and base type automation entries again, and the CSS variables if the CSS file changed.
+func (a *App) reloadCompilationData(data *compilationData, changed []string) (*compilationData, error) {
+	cssVariables := data.cssVariables
+	if slices.Contains(changed, filepath.Clean(a.config.StyleColorCSSFile)) {
+		reloaded, err := a.loadCSSVariables()
+		if err != nil {
+			return nil, fmt.Errorf("the CSS file could not be loaded: %w", err)
+		}
+		cssVariables = reloaded
+	}
+	return a.loadCompilationData(cssVariables)
+}
+
+// affectedScripts returns the scripts that must be recompiled after the given files changed.
+// A change to the style, CSS or CSV file affects every script; otherwise only new scripts and scripts reading a changed file are affected.
+func (a *App) affectedScripts(scripts []string, changed []string, dependencies map[string][]string) []string {
+	if a.changesSharedFiles(changed) {
+		return scripts
+	}
+
+	affected := make([]string, 0)
+	for _, script := range scripts {
+		scriptDependencies, known := dependencies[filepath.Clean(script)]
+		if !known {
+			affected = append(affected, script)
+			continue
+		}
+
+		for _, file := range changed {
+			if slices.Contains(scriptDependencies, file) {
+				affected = append(affected, script)
+				break
+			}
+		}
+	}
+	return affected
+}
+
+// snapshotWatchedFiles records the state of every script in the input directory,
+// every file a compiled script imported and the style, CSS and CSV files.
+func (a *App) snapshotWatchedFiles(dependencies map[string][]string) map[string]fileState {
+	files := []string{a.config.StyleJSONFile, a.config.StyleColorCSSFile, a.config.BaseTypeCSVFile}
+
+	// A failing listing is handled by the rebuild; here it just means no scripts are watched.
+	scripts, _ := listFilesWithExtension(a.config.RuleforgeInputDir, ".rf")
+	files = append(files, scripts...)
+
+	for _, scriptDependencies := range dependencies {
+		files = append(files, scriptDependencies...)
+	}
+
+	snapshot := make(map[string]fileState, len(files))
+	for _, file := range files {
+		file = filepath.Clean(file)
+		info, err := os.Stat(file)
+		if err != nil {
+			snapshot[file] = fileState{}
+			continue
+		}
+		snapshot[file] = fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+	}
+	return snapshot
+}
+
+// changedFiles returns the files that were added, removed or modified between two snapshots, sorted.
+func changedFiles(previous, current map[string]fileState) []string {
+	changed := make([]string, 0)
+	for file, state := range current {
+		if previousState, ok := previous[file]; !ok || !previousState.equal(state) {
+			changed = append(changed, file)
+		}
+	}
+	for file := range previous {
+		if _, ok := current[file]; !ok {
+			changed = append(changed, file)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+func cleanPaths(paths []string) []string {
+	cleaned := make([]string, len(paths))
+	for i, p := range paths {
+		cleaned[i] = filepath.Clean(p)
+	}
+	return cleaned
+}
+
+func baseNames(paths []string) []string {
+	names := make([]string, len(paths))
+	for i, p := range paths {
+		names[i] = filepath.Base(p)
+	}
+	return names
+}
@@ -0,0 +1,154 @@
+// Package conditions is the registry of the condition identifiers of the script language.
+// The lexer, the validator and the compiler are all driven by it, so a new condition only needs an entry here.
+package conditions
+
+import "slices"
+
+// ValueKind is the kind of value a condition is compared with.
+type ValueKind int
+
+const (
+	Numeric ValueKind = iota
+	Boolean
+	// StringList conditions take one or more strings, e.g. item classes or base types.
+	StringList
+	// SocketSpec conditions take socket specifications like "5" or "3RGB".
+	SocketSpec
+	// RarityEnum conditions take one of Normal, Magic, Rare and Unique.
+	RarityEnum
+	// InfluenceEnum conditions take one or more influences, e.g. Shaper or Elder, or None.
+	InfluenceEnum
+	// ModList conditions take one or more mod names and can count how many of them match, e.g. `>=2`.
+	ModList
+)
+
+func (k ValueKind) String() string {
+	switch k {
+	case Numeric:
+		return "number"
+	case Boolean:
+		return "boolean"
+	case StringList:
+		return "string list"
+	case SocketSpec:
+		return "socket specification"
+	case RarityEnum:
+		return "rarity"
+	case InfluenceEnum:
+		return "influence"
+	case ModList:
+		return "mod list"
+	default:
+		return "unknown"
+	}
+}
+
+// AllowsMultipleValues reports whether PoE accepts several values on one condition line, any of which may match.
+func (k ValueKind) AllowsMultipleValues() bool {
+	return k == StringList || k == RarityEnum || k == InfluenceEnum || k == ModList
+}
+
+// AllowsCount reports whether conditions of the kind can use counted operators like `>=2`.
+func (k ValueKind) AllowsCount() bool {
+	return k == ModList
+}
+
+// Quoted reports whether values of the kind are written in quotes; PoE expects influences as bare words.
+func (k ValueKind) Quoted() bool {
+	return k != InfluenceEnum
+}
+
+// ContainsOperator matches values that contain the given text. It compiles to PoE's bare form, e.g. `BaseType "Ring"`,
+// whereas `==` only matches values that equal the text.
+const ContainsOperator = "~="
+
+var (
+	comparisonOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+	equalityOperators   = []string{"==", "!="}
+	textOperators       = []string{"==", "!=", ContainsOperator}
+)
+
+// Identifier describes a condition identifier and the PoE filter condition it compiles to.
+type Identifier struct {
+	// Name is the identifier as written in scripts, e.g. "@area_level".
+	Name string
+	// Keyword is the PoE filter condition, e.g. "AreaLevel". It is empty for macros.
+	Keyword   string
+	Kind      ValueKind
+	Operators []string
+	// Range bounds the values of numeric identifiers; nil means any number.
+	Range *Range
+	// Order is the weight that sorts conditions within a compiled block; lower weights come first.
+	Order int
+	// Macro marks identifiers that the compiler expands into other conditions instead of emitting them.
+	Macro bool
+}
+
+// AllowsOperator reports whether the identifier can be compared with the given operator.
+func (i Identifier) AllowsOperator(operator string) bool {
+	return slices.Contains(i.Operators, operator)
+}
+
+// AllowsCountedOperator reports whether the identifier can be compared with the operator followed by a count, e.g. `>=2`.
+func (i Identifier) AllowsCountedOperator(operator string) bool {
+	return i.Kind.AllowsCount() && slices.Contains(comparisonOperators, operator)
+}
+
+var registry = []Identifier{
+	{Name: "@quality", Keyword: "Quality", Kind: Numeric, Operators: comparisonOperators, Order: 1},
+	{Name: "@corrupted", Keyword: "Corrupted", Kind: Boolean, Operators: equalityOperators, Order: 2},
+	{Name: "@fractured", Keyword: "FracturedItem", Kind: Boolean, Operators: equalityOperators, Order: 3},
+	{Name: "@identified", Keyword: "Identified", Kind: Boolean, Operators: equalityOperators, Order: 4},
+	{Name: "@mirrored", Keyword: "Mirrored", Kind: Boolean, Operators: equalityOperators, Order: 5},
+	{Name: "@replica", Keyword: "Replica", Kind: Boolean, Operators: equalityOperators, Order: 6},
+	{Name: "@scourged", Keyword: "Scourged", Kind: Boolean, Operators: equalityOperators, Order: 7},
+	{Name: "@synthesised_item", Keyword: "SynthesisedItem", Kind: Boolean, Operators: equalityOperators, Order: 8},
+	{Name: "@shaper_item", Keyword: "ShaperItem", Kind: Boolean, Operators: equalityOperators, Order: 9},
+	{Name: "@elder_item", Keyword: "ElderItem", Kind: Boolean, Operators: equalityOperators, Order: 10},
+	{Name: "@transfigured_gem", Keyword: "TransfiguredGem", Kind: Boolean, Operators: equalityOperators, Order: 11},
+	{Name: "@blighted_map", Keyword: "BlightedMap", Kind: Boolean, Operators: equalityOperators, Order: 12},
+	{Name: "@uber_blighted_map", Keyword: "UberBlightedMap", Kind: Boolean, Operators: equalityOperators, Order: 13},
+	{Name: "@zana_memory", Keyword: "ZanaMemory", Kind: Boolean, Operators: equalityOperators, Order: 14},
+	{Name: "@alternate_quality", Keyword: "AlternateQuality", Kind: Boolean, Operators: equalityOperators, Order: 15},
+	{Name: "@any_enchantment", Keyword: "AnyEnchantment", Kind: Boolean, Operators: equalityOperators, Order: 16},
+	{Name: "@base_armour", Keyword: "BaseArmour", Kind: Numeric, Operators: comparisonOperators, Order: 17},
+	{Name: "@base_evasion", Keyword: "BaseEvasion", Kind: Numeric, Operators: comparisonOperators, Order: 18},
+	{Name: "@base_energy_shield", Keyword: "BaseEnergyShield", Kind: Numeric, Operators: comparisonOperators, Order: 19},
+	{Name: "@base_ward", Keyword: "BaseWard", Kind: Numeric, Operators: comparisonOperators, Order: 20},
+	{Name: "@stack_size", Keyword: "StackSize", Kind: Numeric, Operators: comparisonOperators, Order: 21},
+	{Name: "@height", Keyword: "Height", Kind: Numeric, Operators: comparisonOperators, Order: 22},
+	{Name: "@width", Keyword: "Width", Kind: Numeric, Operators: comparisonOperators, Order: 23},
+	{Name: "@item_level", Keyword: "ItemLevel", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 1, Max: 100}, Order: 24},
+	{Name: "@drop_level", Keyword: "DropLevel", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 1, Max: 100}, Order: 25},
+	{Name: "@gem_level", Keyword: "GemLevel", Kind: Numeric, Operators: comparisonOperators, Order: 26},
+	{Name: "@area_level", Keyword: "AreaLevel", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 1, Max: 100}, Order: 27},
+	{Name: "@map_tier", Keyword: "MapTier", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 1, Max: 17}, Order: 28},
+	{Name: "@memory_strands", Keyword: "MemoryStrands", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 0, Max: 100}, Order: 29},
+	{Name: "@sockets", Keyword: "Sockets", Kind: SocketSpec, Operators: comparisonOperators, Order: 30},
+	{Name: "@socket_group", Keyword: "SocketGroup", Kind: SocketSpec, Operators: comparisonOperators, Order: 31},
+	{Name: "@links", Keyword: "LinkedSockets", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 0, Max: 6}, Order: 32},
+	{Name: "@has_influence", Keyword: "HasInfluence", Kind: InfluenceEnum, Operators: equalityOperators, Order: 33},
+	{Name: "@item_class", Keyword: "Class", Kind: StringList, Operators: textOperators, Order: 34},
+	{Name: "@item_type", Keyword: "BaseType", Kind: StringList, Operators: textOperators, Order: 35},
+	{Name: "@rarity", Keyword: "Rarity", Kind: RarityEnum, Operators: comparisonOperators, Order: 36},
+	{Name: "@has_searing_exarch_implicit", Keyword: "HasSearingExarchImplicit", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 0, Max: 6}, Order: 37},
+	{Name: "@has_eater_of_worlds_implicit", Keyword: "HasEaterOfWorldsImplicit", Kind: Numeric, Operators: comparisonOperators, Range: &Range{Min: 0, Max: 6}, Order: 38},
+	{Name: "@has_explicit_mod", Keyword: "HasExplicitMod", Kind: ModList, Operators: textOperators, Order: 39},
+	{Name: "@has_implicit_mod", Keyword: "HasImplicitMod", Kind: Boolean, Operators: equalityOperators, Order: 40},
+	{Name: "@enchantment_passive_node", Keyword: "EnchantmentPassiveNode", Kind: StringList, Operators: textOperators, Order: 41},
+	{Name: "@class_use", Kind: Boolean, Operators: equalityOperators, Order: 100, Macro: true},
+}
+
+// All returns every identifier, in the order of their Order weights.
+func All() []Identifier {
+	return slices.Clone(registry)
+}
+
+// Lookup returns the identifier with the given name, e.g. "@rarity".
+func Lookup(name string) (Identifier, bool) {
+	index := slices.IndexFunc(registry, func(identifier Identifier) bool { return identifier.Name == name })
+	if index == -1 {
+		return Identifier{}, false
+	}
+	return registry[index], true
+}
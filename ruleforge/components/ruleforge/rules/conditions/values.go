@@ -0,0 +1,72 @@
+package conditions
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Range is the inclusive range of values a numeric condition accepts.
+type Range struct {
+	Min, Max int
+}
+
+var (
+	booleanValues   = []string{"True", "False"}
+	rarityValues    = []string{"Normal", "Magic", "Rare", "Unique"}
+	influenceValues = []string{"Shaper", "Elder", "Crusader", "Hunter", "Redeemer", "Warlord", "None"}
+
+	// socketSpecPattern matches an optional socket count followed by socket colours, e.g. "5", "RGB" or "6WW".
+	socketSpecPattern = regexp.MustCompile(`^[0-6]?[RGBWAD]{0,6}$`)
+)
+
+// CheckValue reports why the value is not valid for the identifier, or nil if it is.
+func (i Identifier) CheckValue(value string) error {
+	switch i.Kind {
+	case Numeric:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s takes a whole number, got %q", i.Name, value)
+		}
+		if i.Range != nil && (number < i.Range.Min || number > i.Range.Max) {
+			return fmt.Errorf("%s must be between %d and %d, got %d", i.Name, i.Range.Min, i.Range.Max, number)
+		}
+	case Boolean:
+		return checkEnum(i.Name, value, booleanValues)
+	case RarityEnum:
+		return checkEnum(i.Name, value, rarityValues)
+	case InfluenceEnum:
+		return checkEnum(i.Name, value, influenceValues)
+	case SocketSpec:
+		if value == "" || !socketSpecPattern.MatchString(value) {
+			return fmt.Errorf("%s takes a socket count and/or colours (R, G, B, W, A, D), e.g. \"5\" or \"3RGB\", got %q", i.Name, value)
+		}
+	case StringList, ModList:
+		if value == "" {
+			return fmt.Errorf("%s does not take empty values", i.Name)
+		}
+		// PoE reads a quoted value up to the next quote and has no escapes, so a quote cannot be part of a value.
+		if strings.ContainsRune(value, '"') {
+			return fmt.Errorf("%s cannot take %q, PoE filters cannot hold a double quote within a value", i.Name, value)
+		}
+	}
+	return nil
+}
+
+// CheckValueCount reports an error if the identifier cannot take the given number of values on one line.
+func (i Identifier) CheckValueCount(count int) error {
+	if count > 1 && !i.Kind.AllowsMultipleValues() {
+		return fmt.Errorf("%s takes a single value, got %d", i.Name, count)
+	}
+	return nil
+}
+
+func checkEnum(name, value string, allowed []string) error {
+	for _, candidate := range allowed {
+		if strings.EqualFold(candidate, value) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s takes one of %s, got %q", name, strings.Join(allowed, ", "), value)
+}
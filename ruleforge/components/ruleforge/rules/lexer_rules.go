@@ -3,6 +3,7 @@ package rules
 import (
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/rules"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/rules/special"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/conditions"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 )
 
@@ -14,7 +15,6 @@ var (
 	numberRule                    = rules.NewNumberRule("NumberLexer", symbols.NumberToken)
 	whitespaceRule                = rules.NewWhitespaceLexingRule(symbols.WhitespaceToken, "WhitespaceLexer")
 	identifierAllowedSpecialChars = rules.NewCharacterOptionLexingRule([]rune{'.', '_'}, symbols.IdentifierValueToken, "identifierAllowedSpecialChars")
-	quotedAllowedSpecialChars     = rules.NewCharacterOptionLexingRule([]rune{'[', ']', '-', '/', '\''}, symbols.IdentifierValueToken, "quotedIdentifierAllowedSpecialChars")
 	ruleStrictnessIndicator       = rules.NewSpecificCharacterLexingRule('#', symbols.RuleStrictnessIndicatorToken, "ruleStrictnessIndicator")
 
 	// Composite rules built from the components above.
@@ -23,11 +23,6 @@ var (
 		symbols.IdentifierKeyToken, "unquotedIdentifierChars",
 		letterRule, numberRule, identifierAllowedSpecialChars,
 	)
-	// This rule defines what a quoted identifier can contain.
-	quotedIdentifierCharsRule = rules.NewOrLexingRule(
-		symbols.IdentifierValueToken, "quotedIdentifierChars",
-		numberRule, letterRule, whitespaceRule, identifierAllowedSpecialChars, quotedAllowedSpecialChars,
-	)
 
 	// Skip everything from "!!" to the end of the line (but keep the newline itself).
 	lineCommentRule = special.NewLineCommentLexingRule(
@@ -102,22 +97,14 @@ func buildKeywordRules() []rules.LexingRuleInterface[symbols.LexingTokenType] {
 		{"EQUIPMENT", symbols.IdentifierValueToken, "IdentifierValueToken"},
 		{"RULES", symbols.RuleKeywordToken, "RuleKeywordToken"},
 		{"IMPORT", symbols.ImportKeywordToken, "ImportKeywordToken"},
-
-		// Conditions
-		{"@area_level", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@rarity", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@item_type", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@item_class", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@stack_size", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@class_use", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@socket_group", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@height", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@width", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@sockets", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@map_tier", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@quality", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@corrupted", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
-		{"@fractured", symbols.ConditionKeywordToken, "ConditionKeywordLexer"},
+		{"Continue", symbols.ContinueKeywordToken, "ContinueKeywordToken"},
+		{"FOR", symbols.ForKeywordToken, "ForKeywordToken"},
+		{"IN", symbols.InKeywordToken, "InKeywordToken"},
+		{"IF", symbols.IfKeywordToken, "IfKeywordToken"},
+		{"ELSE", symbols.ElseKeywordToken, "ElseKeywordToken"},
+		{"AS", symbols.AsKeywordToken, "AsKeywordToken"},
+		{"MODULE", symbols.ModuleKeywordToken, "ModuleKeywordToken"},
+		{"USE", symbols.UseKeywordToken, "UseKeywordToken"},
 
 		// Builds
 		{"MARAUDER", symbols.MeleeBuildToken, "BuildValueKeywordLexer"},
@@ -130,12 +117,18 @@ func buildKeywordRules() []rules.LexingRuleInterface[symbols.LexingTokenType] {
 		// Other
 		{"var", symbols.VariableKeywordToken, "VariableKeywordLexer"},
 		{"MACRO", symbols.FunctionKeywordToken, "FunctionKeywordToken"},
+		{"DEFINE", symbols.DefineKeywordToken, "DefineKeywordToken"},
 		{"!override", symbols.StyleOverrideToken, "StyleOverrideToken"},
 	}
 
-	output := make([]rules.LexingRuleInterface[symbols.LexingTokenType], len(keywordDefs))
-	for i, def := range keywordDefs {
-		output[i] = special.NewKeywordLexingRule(def.literal, def.symbol, def.token, unquotedIdentifierCharsRule)
+	output := make([]rules.LexingRuleInterface[symbols.LexingTokenType], 0, len(keywordDefs))
+	for _, def := range keywordDefs {
+		output = append(output, special.NewKeywordLexingRule(def.literal, def.symbol, def.token, unquotedIdentifierCharsRule))
+	}
+
+	// Conditions
+	for _, identifier := range conditions.All() {
+		output = append(output, special.NewKeywordLexingRule(identifier.Name, "ConditionKeywordLexer", symbols.ConditionKeywordToken, unquotedIdentifierCharsRule))
 	}
 	return output
 }
@@ -149,13 +142,19 @@ func buildOperatorRules() []rules.LexingRuleInterface[symbols.LexingTokenType] {
 	}{
 		{"=>", symbols.AssignmentOperatorToken, "AssignmentOperatorLexer"},
 		{"->", symbols.ChainOperatorToken, "ChainOperatorANDLexer"},
+		{"|", symbols.AlternationOperatorToken, "AlternationOperatorORLexer"},
 		{"<=", symbols.LessThanOrEqualOperatorToken, "LessThanOrEqualOperatorLexer"},
 		{">=", symbols.GreaterThanOrEqualOperatorToken, "GreaterThanOrEqualOperatorLexer"},
 		{"==", symbols.ExactMatchOperatorToken, "ExactMatchOperatorLexer"},
 		{"!=", symbols.NotEqualToOperatorToken, "NotEqualToOperatorLexer"},
+		{"~=", symbols.ContainsOperatorToken, "ContainsOperatorLexer"},
+		{"..", symbols.RangeOperatorToken, "RangeOperatorLexer"},
 		{"<", symbols.LessThanOperatorToken, "LessThanOperatorLexer"},
 		{">", symbols.GreaterThanOperatorToken, "GreaterThanOperatorLexer"},
 		{"+", symbols.StyleCombineToken, "StyleCombineToken"},
+		{"-", symbols.SubtractOperatorToken, "SubtractOperatorLexer"},
+		{"*", symbols.MultiplyOperatorToken, "MultiplyOperatorLexer"},
+		{"/", symbols.DivideOperatorToken, "DivideOperatorLexer"},
 	}
 
 	output := make([]rules.LexingRuleInterface[symbols.LexingTokenType], len(operatorDefs))
@@ -171,7 +170,7 @@ func buildOperatorRules() []rules.LexingRuleInterface[symbols.LexingTokenType] {
 func buildLiteralValueRules() []rules.LexingRuleInterface[symbols.LexingTokenType] {
 	return []rules.LexingRuleInterface[symbols.LexingTokenType]{
 		numberRule,
-		special.NewQuotedValueRule("IdentifierValueLexer", symbols.IdentifierValueToken, false, quotedIdentifierCharsRule),
+		special.NewQuotedValueRule("IdentifierValueLexer", symbols.IdentifierValueToken, false),
 	}
 }
 
@@ -193,8 +192,9 @@ func buildStructuralRules() []rules.LexingRuleInterface[symbols.LexingTokenType]
 		rules.NewSpecificCharacterLexingRule('}', symbols.CloseCurlyBracketToken, "CloseCurlyBracketLexer"),
 		rules.NewSpecificCharacterLexingRule('[', symbols.OpenSquareBracketToken, "OpenSquareBracketToken"),
 		rules.NewSpecificCharacterLexingRule(']', symbols.CloseSquareBracketToken, "CloseSquareBracketToken"),
-		rules.NewSpecificCharacterLexingRule('(', symbols.IgnoreToken, "OpenParenthesesToken"),
-		rules.NewSpecificCharacterLexingRule(')', symbols.IgnoreToken, "CloseParenthesesToken"),
+		rules.NewSpecificCharacterLexingRule('(', symbols.OpenParenthesisToken, "OpenParenthesesToken"),
+		rules.NewSpecificCharacterLexingRule(')', symbols.CloseParenthesisToken, "CloseParenthesesToken"),
+		rules.NewSpecificCharacterLexingRule(',', symbols.CommaToken, "CommaToken"),
 		rules.NewCharacterOptionLexingRule([]rune{'\r', '\n'}, symbols.NewLineToken, "newline"),
 		ruleStrictnessIndicator,
 		whitespaceRule,
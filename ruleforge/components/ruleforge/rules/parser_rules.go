@@ -1,7 +1,10 @@
 package rules
 
 import (
+	"slices"
+
 	// Use aliased imports for brevity and clarity.
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/atomic"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/composite"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/rules/conditional"
@@ -44,6 +47,10 @@ func GetParsingRules() []shared.ParsingRuleInterface[symbols.LexingTokenType] {
 		sectionRule(),
 		variableRule(),
 		importRule(),
+		moduleRule(),
+		useRule(),
+		macroDefinitionRule(),
+		conditionalRule(topLevelBranchRule),
 		// Fallbacks for any remaining standalone tokens.
 		atomic.NewSingleTokenRule(symbols.ParseSymbolWhitespace.String(), symbols.NewLineToken),
 		atomic.NewSingleTokenRule(symbols.ParseSymbolWhitespace.String(), symbols.WhitespaceToken),
@@ -51,11 +58,61 @@ func GetParsingRules() []shared.ParsingRuleInterface[symbols.LexingTokenType] {
 	}
 }
 
+// GetRecoveryOptions returns how the parser resynchronizes after a syntax error.
+// Skipping stops at the next SECTION, var or DEFINE, or at the } closing the broken block; RULES blocks met on the way are parsed on their own.
+// Within a RULES block, every rule, macro invocation and loop is parsed on its own, so each broken one is reported.
+func GetRecoveryOptions() parsing.RecoveryOptions[symbols.LexingTokenType] {
+	return parsing.RecoveryOptions[symbols.LexingTokenType]{
+		ErrorSymbol:  symbols.ParseSymbolAny.String(),
+		BlockOpen:    symbols.OpenCurlyBracketToken,
+		BlockClose:   symbols.CloseCurlyBracketToken,
+		ResumeTokens: []symbols.LexingTokenType{symbols.SectionKeywordToken, symbols.VariableKeywordToken, symbols.DefineKeywordToken},
+		NestedRules: map[symbols.LexingTokenType]shared.ParsingRuleInterface[symbols.LexingTokenType]{
+			symbols.RuleKeywordToken: ruleSectionRule(),
+		},
+		ItemScope: symbols.RuleKeywordToken,
+		ItemRules: map[symbols.LexingTokenType]shared.ParsingRuleInterface[symbols.LexingTokenType]{
+			symbols.ConditionAssignmentKeywordToken: ruleExpressionRule(),
+			symbols.FunctionKeywordToken:            macroExpressionRule(),
+			symbols.ForKeywordToken:                 forLoopRule(),
+		},
+	}
+}
+
+// importRule matches `IMPORT "file.rf"`, optionally followed by `AS name` to namespace the variables of the file.
 func importRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
-	return seq(
+	aliased := seq(
 		symbols.ParseSymbolImport,
 		token(symbols.ParseSymbolKey, symbols.ImportKeywordToken),
 		token(symbols.ParseSymbolValue, symbols.IdentifierValueToken),
+		token(symbols.ParseSymbolKeyword, symbols.AsKeywordToken),
+		token(symbols.ParseSymbolIdentifier, symbols.IdentifierKeyToken),
+	)
+	plain := seq(
+		symbols.ParseSymbolImport,
+		token(symbols.ParseSymbolKey, symbols.ImportKeywordToken),
+		token(symbols.ParseSymbolValue, symbols.IdentifierValueToken),
+	)
+	return composite.NewChoiceRule(symbols.ParseSymbolImport.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{aliased, plain})
+}
+
+// moduleRule matches the `MODULE name` header of a library file.
+// A module has no METADATA block; its sections are only included where a script USEs them.
+func moduleRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	return seq(
+		symbols.ParseSymbolModule,
+		token(symbols.ParseSymbolKeyword, symbols.ModuleKeywordToken),
+		token(symbols.ParseSymbolIdentifier, symbols.IdentifierKeyToken),
+	)
+}
+
+// useRule matches `USE name ["Section A", "Section B"]`, which includes the named sections of an imported module in that order.
+func useRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	return seq(
+		symbols.ParseSymbolUse,
+		token(symbols.ParseSymbolKeyword, symbols.UseKeywordToken),
+		token(symbols.ParseSymbolIdentifier, symbols.IdentifierKeyToken),
+		valueListRule(token(symbols.ParseSymbolValue, symbols.IdentifierValueToken)),
 	)
 }
 
@@ -86,6 +143,7 @@ func sectionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
 		metadataRule(symbols.ParseSymbolSectionMetadata),
 		conditionListRule(),
 		ruleSectionRule(),
+		conditionalRule(sectionBranchRule),
 		whitespaceOptional, // Allow whitespace between inner sections
 	)
 
@@ -111,16 +169,86 @@ func conditionListRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
 }
 
 func ruleSectionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
-	rules := composite.NewRepetitionRule[symbols.LexingTokenType](symbols.ParseSymbolRules.String(),
+	return seq(symbols.ParseSymbolRuleSection,
+		token(symbols.ParseSymbolKeyword, symbols.RuleKeywordToken),
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenCurlyBracketToken),
+		ruleListRule(),
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseCurlyBracketToken),
+	)
+}
+
+// ruleListRule matches the content of a RULES block: rules, macro invocations, loops and conditional rules.
+func ruleListRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	return composite.NewRepetitionRule[symbols.LexingTokenType](symbols.ParseSymbolRules.String(),
 		ruleExpressionRule(),
 		macroExpressionRule(),
+		forLoopRule(),
+		conditionalRule(ruleListRule),
 		whitespaceOptional,
 	)
+}
+
+// forLoopRule matches `FOR $x IN ["A", "B"] { <rules> }` and `FOR $t IN 1..16 { <rules> }`.
+// A variable in place of the values is matched too, so that the loop unroller can explain why it cannot be iterated over.
+func forLoopRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	listValue := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
+		[]symbols.LexingTokenType{symbols.IdentifierValueToken, symbols.NumberToken},
+	)
 
-	return seq(symbols.ParseSymbolRuleSection,
-		token(symbols.ParseSymbolKeyword, symbols.RuleKeywordToken),
+	valueRange := seq(symbols.ParseSymbolRange,
+		token(symbols.ParseSymbolValue, symbols.NumberToken),
+		token(symbols.ParseSymbolOperator, symbols.RangeOperatorToken),
+		token(symbols.ParseSymbolValue, symbols.NumberToken),
+	)
+
+	// The body is built lazily, as it can contain loops itself.
+	body := composite.NewLazyRule(symbols.ParseSymbolRules.String(), ruleListRule)
+
+	return seq(symbols.ParseSymbolForLoop,
+		token(symbols.ParseSymbolKeyword, symbols.ForKeywordToken),
+		token(symbols.ParseSymbolKey, symbols.VariableReferenceToken),
+		token(symbols.ParseSymbolKeyword, symbols.InKeywordToken),
+		composite.NewChoiceRule(symbols.ParseSymbolValue.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+			valueListRule(listValue),
+			valueRange,
+			token(symbols.ParseSymbolValue, symbols.VariableReferenceToken),
+		}),
 		token(symbols.ParseSymbolBlockOperator, symbols.OpenCurlyBracketToken),
-		rules,
+		body,
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseCurlyBracketToken),
+	)
+}
+
+// macroDefinitionRule matches `DEFINE MACRO "name" ($param1, $param2) { <rules> }`.
+// The body holds the same rules as a RULES block and is expanded wherever the macro is invoked.
+func macroDefinitionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	parameter := token(symbols.ParseSymbolKey, symbols.VariableReferenceToken)
+	followingParameters := composite.NewRepetitionRule[symbols.LexingTokenType](
+		symbols.ParseSymbolChainedValues.String(),
+		whitespaceOptional,
+		seq(symbols.ParseSymbolChainedValues, token(symbols.ParseSymbolBlockOperator, symbols.CommaToken), parameter),
+	)
+
+	parameters := composite.NewChoiceRule(symbols.ParseSymbolMacroParameters.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		seq(symbols.ParseSymbolMacroParameters,
+			token(symbols.ParseSymbolBlockOperator, symbols.OpenParenthesisToken),
+			parameter,
+			followingParameters,
+			token(symbols.ParseSymbolBlockOperator, symbols.CloseParenthesisToken),
+		),
+		seq(symbols.ParseSymbolMacroParameters,
+			token(symbols.ParseSymbolBlockOperator, symbols.OpenParenthesisToken),
+			token(symbols.ParseSymbolBlockOperator, symbols.CloseParenthesisToken),
+		),
+	})
+
+	return seq(symbols.ParseSymbolMacroDefinition,
+		token(symbols.ParseSymbolKeyword, symbols.DefineKeywordToken),
+		token(symbols.ParseSymbolKeyword, symbols.FunctionKeywordToken),
+		token(symbols.ParseSymbolValue, symbols.IdentifierValueToken),
+		parameters,
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenCurlyBracketToken),
+		ruleListRule(),
 		token(symbols.ParseSymbolBlockOperator, symbols.CloseCurlyBracketToken),
 	)
 }
@@ -132,12 +260,22 @@ func ruleExpressionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
 		},
 	)
 
+	// `$Show+Continue` lets matching items fall through to later rules.
+	actionOpts := composite.NewChoiceRule(symbols.ParseSymbolValue.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		seq(symbols.ParseSymbolRuleAction,
+			token(symbols.ParseSymbolValue, symbols.VariableReferenceToken),
+			token(symbols.ParseSymbolOperator, symbols.StyleCombineToken),
+			token(symbols.ParseSymbolKeyword, symbols.ContinueKeywordToken),
+		),
+		valueOpts,
+	})
+
 	normalExpression := seq(symbols.ParseSymbolRuleExpression,
 		conditionRule(),
 		token(symbols.ParseSymbolOperator, symbols.AssignmentOperatorToken),
 		valueOpts,
 		token(symbols.ParseSymbolOperator, symbols.AssignmentOperatorToken),
-		valueOpts,
+		actionOpts,
 	)
 
 	elaborateExpression := seq(symbols.ParseSymbolRuleExpression,
@@ -145,7 +283,7 @@ func ruleExpressionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
 		token(symbols.ParseSymbolOperator, symbols.AssignmentOperatorToken),
 		valueOpts,
 		token(symbols.ParseSymbolOperator, symbols.AssignmentOperatorToken),
-		valueOpts,
+		actionOpts,
 		token(symbols.ParseSymbolKeyword, symbols.RuleStrictnessIndicatorToken),
 		strictnessAssignmentValues,
 	)
@@ -156,12 +294,13 @@ func ruleExpressionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
 func macroExpressionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
 	// --- Parameter Rules ---
 
-	// 1. Define a new rule that accepts either a variable reference or an identifier.
+	// 1. Define a new rule that accepts a variable reference, an identifier or a number.
 	//    This creates a <Value> node in the parse tree.
 	parameterValueOptions := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
 		[]symbols.LexingTokenType{
 			symbols.VariableReferenceToken,
 			symbols.IdentifierValueToken,
+			symbols.NumberToken,
 		},
 	)
 
@@ -191,77 +330,244 @@ func macroExpressionRule() shared.ParsingRuleInterface[symbols.LexingTokenType]
 	)
 }
 
+// --- Conditional Compilation Rules ---
+
+// conditionalRule matches `IF <subject> <operator> <value> { <branch> }`, optionally followed by `ELSE { <branch> }`
+// or by `ELSE IF ...`. The subject is BUILD, STRICTNESS or a define like `$league`.
+// branch builds the content allowed within the blocks; it is built lazily, as branches can hold conditionals themselves.
+func conditionalRule(branch func() shared.ParsingRuleInterface[symbols.LexingTokenType]) shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	subject := conditional.NewChoiceTokenRule(symbols.ParseSymbolKey.String(),
+		[]symbols.LexingTokenType{symbols.BuildKeywordToken, symbols.StrictnessKeywordToken, symbols.VariableReferenceToken},
+	)
+	operator := conditional.NewChoiceTokenRule(symbols.ParseSymbolOperator.String(),
+		[]symbols.LexingTokenType{
+			symbols.GreaterThanOrEqualOperatorToken, symbols.LessThanOrEqualOperatorToken,
+			symbols.GreaterThanOperatorToken, symbols.LessThanOperatorToken, symbols.ExactMatchOperatorToken, symbols.NotEqualToOperatorToken,
+		},
+	)
+	value := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
+		[]symbols.LexingTokenType{
+			symbols.IdentifierValueToken, symbols.NumberToken,
+			symbols.AllKeywordToken, symbols.SoftKeywordToken, symbols.SemiStrictKeywordToken,
+			symbols.StrictKeywordToken, symbols.SuperStrictKeywordToken,
+			symbols.MeleeBuildToken, symbols.DexBuildToken, symbols.SpellBuildToken,
+			symbols.MeleeSpellHybridBuildToken, symbols.MeleeDexHybridBuildToken, symbols.SpellDexHybridBuildToken,
+		},
+	)
+
+	ifParts := []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		token(symbols.ParseSymbolKeyword, symbols.IfKeywordToken),
+		seq(symbols.ParseSymbolCompileCondition, subject, operator, value),
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenCurlyBracketToken),
+		composite.NewLazyRule(symbols.ParseSymbolConditionalBody.String(), branch),
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseCurlyBracketToken),
+	}
+
+	elseBlock := seq(symbols.ParseSymbolElseBranch,
+		token(symbols.ParseSymbolKeyword, symbols.ElseKeywordToken),
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenCurlyBracketToken),
+		composite.NewLazyRule(symbols.ParseSymbolConditionalBody.String(), branch),
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseCurlyBracketToken),
+	)
+	elseIf := seq(symbols.ParseSymbolElseBranch,
+		token(symbols.ParseSymbolKeyword, symbols.ElseKeywordToken),
+		composite.NewLazyRule(symbols.ParseSymbolConditional.String(), func() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+			return conditionalRule(branch)
+		}),
+	)
+
+	return composite.NewChoiceRule(symbols.ParseSymbolConditional.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		seq(symbols.ParseSymbolConditional, append(slices.Clone(ifParts), elseIf)...),
+		seq(symbols.ParseSymbolConditional, append(slices.Clone(ifParts), elseBlock)...),
+		seq(symbols.ParseSymbolConditional, ifParts...),
+	})
+}
+
+// topLevelBranchRule matches the content of a top-level conditional: sections and variables.
+func topLevelBranchRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	return composite.NewRepetitionRule[symbols.LexingTokenType](symbols.ParseSymbolConditionalBody.String(),
+		sectionRule(),
+		variableRule(),
+		conditionalRule(topLevelBranchRule),
+		whitespaceOptional,
+	)
+}
+
+// sectionBranchRule matches the content of a conditional within a section: SECTION_CONDITIONS and RULES blocks.
+func sectionBranchRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	return composite.NewRepetitionRule[symbols.LexingTokenType](symbols.ParseSymbolConditionalBody.String(),
+		conditionListRule(),
+		ruleSectionRule(),
+		conditionalRule(sectionBranchRule),
+		whitespaceOptional,
+	)
+}
+
 // --- Assignment and Declaration Rules ---
 
 func conditionRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	// The first condition is introduced by WHERE, every following one by `->` (AND) or `|` (OR).
+	// Groups in parentheses can appear wherever a single condition can.
+	connectors := conditional.NewChoiceTokenRule(symbols.ParseSymbolOperator.String(),
+		[]symbols.LexingTokenType{symbols.ChainOperatorToken, symbols.AlternationOperatorToken},
+	)
+
+	return makeChainedRule(
+		symbols.ParseSymbolCondition,
+		symbols.ParseSymbolChainedConditions,
+		conditionTermRule(token(symbols.ParseSymbolKeyword, symbols.ConditionAssignmentKeywordToken)),
+		conditionTermRule(connectors),
+	)
+}
+
+// conditionTermRule matches a single condition or a parenthesized group, each preceded by the given connector.
+// Without a connector it matches the first term inside a group.
+func conditionTermRule(connector shared.ParsingRuleInterface[symbols.LexingTokenType]) shared.ParsingRuleInterface[symbols.LexingTokenType] {
 	// Defines the set of valid comparison operators (e.g., >=, <=, ==).
 	comparisonOps := conditional.NewChoiceTokenRule(symbols.ParseSymbolOperator.String(),
 		[]symbols.LexingTokenType{
 			symbols.GreaterThanOrEqualOperatorToken, symbols.LessThanOrEqualOperatorToken,
 			symbols.GreaterThanOperatorToken, symbols.LessThanOperatorToken, symbols.ExactMatchOperatorToken, symbols.NotEqualToOperatorToken,
+			symbols.ContainsOperatorToken,
 		},
 	)
 
 	// Defines the set of valid value types for a condition.
-	valueOpts := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
+	singleValue := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
 		[]symbols.LexingTokenType{
 			symbols.VariableReferenceToken, symbols.NumberToken, symbols.IdentifierValueToken,
 		},
 	)
+	// Expressions like `$map_start + 5` or `$orbs + ["Mirror of Kalandra"]` are evaluated at compile time.
+	// They are tried first, as a list or value followed by an operator would otherwise leave the rest unmatched.
+	valueOpts := composite.NewChoiceRule(symbols.ParseSymbolValue.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		valueExpressionRule(true),
+		valueListRule(singleValue),
+		singleValue,
+	})
 
-	// Defines the `WHERE <identifier> <op> <value>` part as a single, flat sequence.
-	// This creates one ConditionExpression node with four children.
-	initialPart := seqWithSeparator(whitespaceOptional, symbols.ParseSymbolConditionExpression,
-		token(symbols.ParseSymbolKeyword, symbols.ConditionAssignmentKeywordToken),
-		token(symbols.ParseSymbolIdentifier, symbols.ConditionKeywordToken),
+	// `>=2` requires a number of the values to match, e.g. at least two of a list of mods.
+	countedOp := seq(symbols.ParseSymbolCountedOperator,
 		comparisonOps,
+		token(symbols.ParseSymbolCount, symbols.NumberToken),
+	)
+
+	// The group's content is built lazily, as it contains terms that can be groups themselves.
+	groupContent := composite.NewLazyRule(symbols.ParseSymbolCondition.String(), func() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+		connectors := conditional.NewChoiceTokenRule(symbols.ParseSymbolOperator.String(),
+			[]symbols.LexingTokenType{symbols.ChainOperatorToken, symbols.AlternationOperatorToken},
+		)
+		return makeChainedRule(
+			symbols.ParseSymbolCondition,
+			symbols.ParseSymbolChainedConditions,
+			conditionTermRule(nil),
+			conditionTermRule(connectors),
+		)
+	})
+
+	// `<connector> <identifier> <op> <value>` is a single, flat ConditionExpression node.
+	// The counted form is tried first, as `@area_level >= 84` would otherwise leave the value unmatched.
+	countedExpressionParts := []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		token(symbols.ParseSymbolIdentifier, symbols.ConditionKeywordToken),
+		countedOp,
 		valueOpts,
-	)
-
-	// Defines the `-> <identifier> <op> <value>` part for chained conditions.
-	// This also creates a single, flat ConditionExpression node.
-	chainedPart := seqWithSeparator(whitespaceOptional, symbols.ParseSymbolConditionExpression,
-		token(symbols.ParseSymbolOperator, symbols.ChainOperatorToken),
+	}
+	expressionParts := []shared.ParsingRuleInterface[symbols.LexingTokenType]{
 		token(symbols.ParseSymbolIdentifier, symbols.ConditionKeywordToken),
 		comparisonOps,
 		valueOpts,
-	)
+	}
+	// `<connector> ( <conditions> )` is a ConditionGroup node holding a nested Condition.
+	groupParts := []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenParenthesisToken),
+		groupContent,
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseParenthesisToken),
+	}
+	if connector != nil {
+		countedExpressionParts = append([]shared.ParsingRuleInterface[symbols.LexingTokenType]{connector}, countedExpressionParts...)
+		expressionParts = append([]shared.ParsingRuleInterface[symbols.LexingTokenType]{connector}, expressionParts...)
+		groupParts = append([]shared.ParsingRuleInterface[symbols.LexingTokenType]{connector}, groupParts...)
+	}
 
-	// The makeChainedRule helper correctly assembles the initial part with any
-	// subsequent chained parts.
-	return makeChainedRule(
-		symbols.ParseSymbolCondition,
-		symbols.ParseSymbolChainedConditions,
-		initialPart,
-		chainedPart,
-	)
+	return composite.NewChoiceRule(symbols.ParseSymbolConditionExpression.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		seq(symbols.ParseSymbolConditionExpression, countedExpressionParts...),
+		seq(symbols.ParseSymbolConditionExpression, expressionParts...),
+		seq(symbols.ParseSymbolConditionGroup, groupParts...),
+	})
 }
 
-func variableRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
-	valueOpts := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
+// valueExpressionRule matches values joined by operators, e.g. `"StyleA" + "StyleB"` or `($map_start + 5) * 2`.
+// `+` combines styles, adds numbers or concatenates lists, and `-` also removes values from a list.
+// Operands are single values, lists like `["Exalted Orb", "Divine Orb"]` or parenthesized expressions.
+// With requireOperator, a single value on its own does not match.
+func valueExpressionRule(requireOperator bool) shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	value := conditional.NewChoiceTokenRule(symbols.ParseSymbolValue.String(),
 		[]symbols.LexingTokenType{
 			symbols.NumberToken,
 			symbols.IdentifierValueToken,
 			symbols.VariableReferenceToken,
 		},
 	)
+	// The nested expression is built lazily, as it can contain groups itself.
+	group := seq(symbols.ParseSymbolExpressionGroup,
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenParenthesisToken),
+		composite.NewLazyRule(symbols.ParseSymbolFullValueExpression.String(), func() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+			return valueExpressionRule(false)
+		}),
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseParenthesisToken),
+	)
+	operand := composite.NewChoiceRule(symbols.ParseSymbolValue.String(), []shared.ParsingRuleInterface[symbols.LexingTokenType]{
+		value,
+		valueListRule(value),
+		group,
+	})
 
 	combinedValuePart := seq(symbols.ParseSymbolCombinedValue,
-		token(symbols.ParseSymbolOperator, symbols.StyleCombineToken),
-		valueOpts,
+		conditional.NewChoiceTokenRule(symbols.ParseSymbolOperator.String(), []symbols.LexingTokenType{
+			symbols.StyleCombineToken, symbols.SubtractOperatorToken, symbols.MultiplyOperatorToken, symbols.DivideOperatorToken,
+		}),
+		operand,
 	)
-
 	repeatingCombinedValues := composite.NewRepetitionRule[symbols.LexingTokenType](
 		symbols.ParseSymbolChainedValues.String(),
 		whitespaceOptional,
 		combinedValuePart,
 	)
 
-	fullValueExpression := composite.NewNestedRule[symbols.LexingTokenType](
+	if requireOperator {
+		return composite.NewNestedRule[symbols.LexingTokenType](
+			symbols.ParseSymbolFullValueExpression.String(),
+			operand,
+			whitespaceOptional,
+			combinedValuePart,
+			repeatingCombinedValues,
+		)
+	}
+	return composite.NewNestedRule[symbols.LexingTokenType](
 		symbols.ParseSymbolFullValueExpression.String(),
-		valueOpts,
+		operand,
 		repeatingCombinedValues,
 	)
+}
+
+// valueListRule matches a bracketed, comma-separated list of values, e.g. `["of Haast", "Tyrannical"]`.
+func valueListRule(value shared.ParsingRuleInterface[symbols.LexingTokenType]) shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	followingValues := composite.NewRepetitionRule[symbols.LexingTokenType](
+		symbols.ParseSymbolChainedValues.String(),
+		whitespaceOptional,
+		seq(symbols.ParseSymbolChainedValues, token(symbols.ParseSymbolBlockOperator, symbols.CommaToken), value),
+	)
+
+	return seq(symbols.ParseSymbolValueList,
+		token(symbols.ParseSymbolBlockOperator, symbols.OpenSquareBracketToken),
+		value,
+		followingValues,
+		token(symbols.ParseSymbolBlockOperator, symbols.CloseSquareBracketToken),
+	)
+}
+
+func variableRule() shared.ParsingRuleInterface[symbols.LexingTokenType] {
+	fullValueExpression := valueExpressionRule(false)
 
 	optionalOverrideBlock := composite.NewRepetitionRule[symbols.LexingTokenType](
 		symbols.ParseSymbolOptionalOverrides.String(),
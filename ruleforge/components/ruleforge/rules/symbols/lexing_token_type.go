@@ -22,6 +22,9 @@ const (
 	CloseCurlyBracketToken
 	OpenSquareBracketToken
 	CloseSquareBracketToken
+	OpenParenthesisToken
+	CloseParenthesisToken
+	CommaToken
 
 	// OPERATORS
 	AssignmentOperatorToken
@@ -34,6 +37,12 @@ const (
 	StyleCombineToken
 	RuleStrictnessIndicatorToken
 	NotEqualToOperatorToken
+	AlternationOperatorToken
+	ContainsOperatorToken
+	RangeOperatorToken
+	SubtractOperatorToken
+	MultiplyOperatorToken
+	DivideOperatorToken
 
 	// KEYWORDS
 	MetadataKeywordToken
@@ -54,6 +63,15 @@ const (
 	RuleKeywordToken
 	BuildKeywordToken
 	ImportKeywordToken
+	ContinueKeywordToken
+	DefineKeywordToken
+	ForKeywordToken
+	InKeywordToken
+	IfKeywordToken
+	ElseKeywordToken
+	AsKeywordToken
+	ModuleKeywordToken
+	UseKeywordToken
 
 	// CLASSES
 	MeleeSpellHybridBuildToken
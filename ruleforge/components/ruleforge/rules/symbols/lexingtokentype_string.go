@@ -20,48 +20,66 @@ func _() {
 	_ = x[CloseCurlyBracketToken-9]
 	_ = x[OpenSquareBracketToken-10]
 	_ = x[CloseSquareBracketToken-11]
-	_ = x[AssignmentOperatorToken-12]
-	_ = x[ChainOperatorToken-13]
-	_ = x[GreaterThanOrEqualOperatorToken-14]
-	_ = x[LessThanOrEqualOperatorToken-15]
-	_ = x[GreaterThanOperatorToken-16]
-	_ = x[LessThanOperatorToken-17]
-	_ = x[ExactMatchOperatorToken-18]
-	_ = x[StyleCombineToken-19]
-	_ = x[RuleStrictnessIndicatorToken-20]
-	_ = x[NotEqualToOperatorToken-21]
-	_ = x[MetadataKeywordToken-22]
-	_ = x[NameKeywordToken-23]
-	_ = x[VersionKeywordToken-24]
-	_ = x[StrictnessKeywordToken-25]
-	_ = x[AllKeywordToken-26]
-	_ = x[SoftKeywordToken-27]
-	_ = x[SemiStrictKeywordToken-28]
-	_ = x[StrictKeywordToken-29]
-	_ = x[SuperStrictKeywordToken-30]
-	_ = x[VariableKeywordToken-31]
-	_ = x[SectionConditionsKeywordToken-32]
-	_ = x[ConditionAssignmentKeywordToken-33]
-	_ = x[ConditionKeywordToken-34]
-	_ = x[SectionKeywordToken-35]
-	_ = x[DescriptionAssignmentKeywordToken-36]
-	_ = x[RuleKeywordToken-37]
-	_ = x[BuildKeywordToken-38]
-	_ = x[ImportKeywordToken-39]
-	_ = x[MeleeSpellHybridBuildToken-40]
-	_ = x[MeleeDexHybridBuildToken-41]
-	_ = x[SpellDexHybridBuildToken-42]
-	_ = x[MeleeBuildToken-43]
-	_ = x[SpellBuildToken-44]
-	_ = x[DexBuildToken-45]
-	_ = x[DotToken-46]
-	_ = x[FunctionKeywordToken-47]
-	_ = x[StyleOverrideToken-48]
+	_ = x[OpenParenthesisToken-12]
+	_ = x[CloseParenthesisToken-13]
+	_ = x[CommaToken-14]
+	_ = x[AssignmentOperatorToken-15]
+	_ = x[ChainOperatorToken-16]
+	_ = x[GreaterThanOrEqualOperatorToken-17]
+	_ = x[LessThanOrEqualOperatorToken-18]
+	_ = x[GreaterThanOperatorToken-19]
+	_ = x[LessThanOperatorToken-20]
+	_ = x[ExactMatchOperatorToken-21]
+	_ = x[StyleCombineToken-22]
+	_ = x[RuleStrictnessIndicatorToken-23]
+	_ = x[NotEqualToOperatorToken-24]
+	_ = x[AlternationOperatorToken-25]
+	_ = x[ContainsOperatorToken-26]
+	_ = x[RangeOperatorToken-27]
+	_ = x[SubtractOperatorToken-28]
+	_ = x[MultiplyOperatorToken-29]
+	_ = x[DivideOperatorToken-30]
+	_ = x[MetadataKeywordToken-31]
+	_ = x[NameKeywordToken-32]
+	_ = x[VersionKeywordToken-33]
+	_ = x[StrictnessKeywordToken-34]
+	_ = x[AllKeywordToken-35]
+	_ = x[SoftKeywordToken-36]
+	_ = x[SemiStrictKeywordToken-37]
+	_ = x[StrictKeywordToken-38]
+	_ = x[SuperStrictKeywordToken-39]
+	_ = x[VariableKeywordToken-40]
+	_ = x[SectionConditionsKeywordToken-41]
+	_ = x[ConditionAssignmentKeywordToken-42]
+	_ = x[ConditionKeywordToken-43]
+	_ = x[SectionKeywordToken-44]
+	_ = x[DescriptionAssignmentKeywordToken-45]
+	_ = x[RuleKeywordToken-46]
+	_ = x[BuildKeywordToken-47]
+	_ = x[ImportKeywordToken-48]
+	_ = x[ContinueKeywordToken-49]
+	_ = x[DefineKeywordToken-50]
+	_ = x[ForKeywordToken-51]
+	_ = x[InKeywordToken-52]
+	_ = x[IfKeywordToken-53]
+	_ = x[ElseKeywordToken-54]
+	_ = x[AsKeywordToken-55]
+	_ = x[ModuleKeywordToken-56]
+	_ = x[UseKeywordToken-57]
+	_ = x[MeleeSpellHybridBuildToken-58]
+	_ = x[MeleeDexHybridBuildToken-59]
+	_ = x[SpellDexHybridBuildToken-60]
+	_ = x[MeleeBuildToken-61]
+	_ = x[SpellBuildToken-62]
+	_ = x[DexBuildToken-63]
+	_ = x[DotToken-64]
+	_ = x[FunctionKeywordToken-65]
+	_ = x[StyleOverrideToken-66]
 }
 
-const _LexingTokenType_name = "IgnoreTokenNewLineTokenWhitespaceTokenNumberTokenLetterTokenIdentifierKeyTokenIdentifierValueTokenVariableReferenceTokenOpenCurlyBracketTokenCloseCurlyBracketTokenOpenSquareBracketTokenCloseSquareBracketTokenAssignmentOperatorTokenChainOperatorTokenGreaterThanOrEqualOperatorTokenLessThanOrEqualOperatorTokenGreaterThanOperatorTokenLessThanOperatorTokenExactMatchOperatorTokenStyleCombineTokenRuleStrictnessIndicatorTokenNotEqualToOperatorTokenMetadataKeywordTokenNameKeywordTokenVersionKeywordTokenStrictnessKeywordTokenAllKeywordTokenSoftKeywordTokenSemiStrictKeywordTokenStrictKeywordTokenSuperStrictKeywordTokenVariableKeywordTokenSectionConditionsKeywordTokenConditionAssignmentKeywordTokenConditionKeywordTokenSectionKeywordTokenDescriptionAssignmentKeywordTokenRuleKeywordTokenBuildKeywordTokenImportKeywordTokenMeleeSpellHybridBuildTokenMeleeDexHybridBuildTokenSpellDexHybridBuildTokenMeleeBuildTokenSpellBuildTokenDexBuildTokenDotTokenFunctionKeywordTokenStyleOverrideToken"
+const _LexingTokenType_name = "IgnoreTokenNewLineTokenWhitespaceTokenNumberTokenLetterTokenIdentifierKeyTokenIdentifierValueTokenVariableReferenceTokenOpenCurlyBracketTokenCloseCurlyBracketTokenOpenSquareBracketTokenCloseSquareBracketTokenOpenParenthesisTokenCloseParenthesisTokenCommaTokenAssignmentOperatorTokenChainOperatorTokenGreaterThanOrEqualOperatorTokenLessThanOrEqualOperatorTokenGreaterThanOperatorTokenLessThanOperatorTokenExactMatchOperatorTokenStyleCombineTokenRuleStrictnessIndicatorTokenNotEqualToOperatorTokenAlternationOperatorTokenContainsOperatorTokenRangeOperatorTokenSubtractOperatorTokenMultiplyOperatorTokenDivideOperatorTokenMetadataKeywordTokenNameKeywordTokenVersionKeywordTokenStrictnessKeywordTokenAllKeywordTokenSoftKeywordTokenSemiStrictKeywordTokenStrictKeywordTokenSuperStrictKeywordTokenVariableKeywordTokenSectionConditionsKeywordTokenConditionAssignmentKeywordTokenConditionKeywordTokenSectionKeywordTokenDescriptionAssignmentKeywordTokenRuleKeywordTokenBuildKeywordTokenImportKeywordTokenContinueKeywordTokenDefineKeywordTokenForKeywordTokenInKeywordTokenIfKeywordTokenElseKeywordTokenAsKeywordTokenModuleKeywordTokenUseKeywordTokenMeleeSpellHybridBuildTokenMeleeDexHybridBuildTokenSpellDexHybridBuildTokenMeleeBuildTokenSpellBuildTokenDexBuildTokenDotTokenFunctionKeywordTokenStyleOverrideToken"
 
-var _LexingTokenType_index = [...]uint16{0, 11, 23, 38, 49, 60, 78, 98, 120, 141, 163, 185, 208, 231, 249, 280, 308, 332, 353, 376, 393, 421, 444, 464, 480, 499, 521, 536, 552, 574, 592, 615, 635, 664, 695, 716, 735, 768, 784, 801, 819, 845, 869, 893, 908, 923, 936, 944, 964, 982}
+var _LexingTokenType_index = [...]uint16{0, 11, 23, 38, 49, 60, 78, 98, 120, 141, 163, 185, 208, 228, 249, 259, 282, 300, 331, 359, 383, 404, 427, 444, 472, 495, 519, 540, 558, 579, 600, 619, 639, 655, 674, 696, 711, 727, 749, 767, 790, 810, 839, 870, 891, 910, 943, 959, 976, 994, 1014, 1032, 1047, 1061, 1075, 1091, 1105, 1123, 1138, 1164, 1188, 1212, 1227, 1242, 1255, 1263, 1283, 1301}
 
 func (i LexingTokenType) String() string {
 	if i < 0 || i >= LexingTokenType(len(_LexingTokenType_index)-1) {
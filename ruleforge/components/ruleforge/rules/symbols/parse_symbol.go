@@ -13,6 +13,7 @@ const (
 	ParseSymbolConditionList       ParseSymbol = "ConditionList"
 	ParseSymbolCondition           ParseSymbol = "Condition"
 	ParseSymbolConditionExpression ParseSymbol = "ConditionExpression"
+	ParseSymbolConditionGroup      ParseSymbol = "ConditionGroup"
 	ParseSymbolAssignment          ParseSymbol = "Assignment"
 	ParseSymbolSectionContent      ParseSymbol = "SectionContent"
 	ParseSymbolConditions          ParseSymbol = "Conditions"
@@ -25,6 +26,18 @@ const (
 	ParseSymbolChainedValues       ParseSymbol = "ChainedValues"
 	ParseSymbolFullValueExpression ParseSymbol = "FullValueExpression"
 	ParseSymbolImport              ParseSymbol = "Import"
+	ParseSymbolValueList           ParseSymbol = "ValueList"
+	ParseSymbolCountedOperator     ParseSymbol = "CountedOperator"
+	ParseSymbolRuleAction          ParseSymbol = "RuleAction"
+	ParseSymbolForLoop             ParseSymbol = "ForLoop"
+	ParseSymbolRange               ParseSymbol = "Range"
+	ParseSymbolConditional         ParseSymbol = "Conditional"
+	ParseSymbolCompileCondition    ParseSymbol = "CompileCondition"
+	ParseSymbolConditionalBody     ParseSymbol = "ConditionalBody"
+	ParseSymbolElseBranch          ParseSymbol = "ElseBranch"
+	ParseSymbolModule              ParseSymbol = "Module"
+	ParseSymbolUse                 ParseSymbol = "Use"
+	ParseSymbolExpressionGroup     ParseSymbol = "ExpressionGroup"
 
 	// --- Common Grammatical Roles ---
 	ParseSymbolKey           ParseSymbol = "Key"
@@ -35,10 +48,13 @@ const (
 	ParseSymbolWhitespace    ParseSymbol = "Whitespace"
 	ParseSymbolAssignments   ParseSymbol = "AssignmentList"
 	ParseSymbolBlockOperator ParseSymbol = "BlockOperator"
+	ParseSymbolCount         ParseSymbol = "Count"
 
 	ParseSymbolMacroExpression ParseSymbol = "MacroExpression"
 	ParseSymbolParameterList   ParseSymbol = "ParameterList"
 	ParseSymbolParameter       ParseSymbol = "Parameter"
+	ParseSymbolMacroDefinition ParseSymbol = "MacroDefinition"
+	ParseSymbolMacroParameters ParseSymbol = "MacroParameters"
 
 	ParseSymbolOverrideTarget         ParseSymbol = "OverrideTarget"
 	ParseSymbolOverrideTargetList     ParseSymbol = "OverrideTargetList"
@@ -0,0 +1,76 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+var strictnessNames = []string{"ALL", "SOFT", "SEMI-STRICT", "STRICT", "SUPER-STRICT"}
+
+// CompileConditionValidator checks the conditions of IF blocks, in every branch, before they are evaluated:
+// STRICTNESS must be compared with a strictness level, BUILD only for equality and defines only numerically with numbers.
+type CompileConditionValidator struct {
+	documentTree []*shared.ParseTree[symbols.LexingTokenType]
+}
+
+func NewCompileConditionValidator(documentTree []*shared.ParseTree[symbols.LexingTokenType]) *CompileConditionValidator {
+	return &CompileConditionValidator{documentTree: documentTree}
+}
+
+// Validate reports every invalid condition together.
+func (c *CompileConditionValidator) Validate() error {
+	var problems diagnostics.List
+	for _, node := range c.documentTree {
+		for _, condition := range node.FindAllSymbolNodes(symbols.ParseSymbolCompileCondition.String()) {
+			if problem := validateCompileCondition(condition); problem != nil {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func validateCompileCondition(condition *shared.ParseTree[symbols.LexingTokenType]) *diagnostics.Diagnostic {
+	if len(condition.Children) != 3 {
+		return nil
+	}
+	subject, operatorNode, valueNode := condition.Children[0], condition.Children[1], condition.Children[2]
+	operator := operatorNode.Token.ValueToString()
+	value := valueNode.Token.ValueToString()
+	equality := operator == "==" || operator == "!="
+
+	switch subject.Token.Type {
+	case symbols.StrictnessKeywordToken:
+		if !slices.Contains(strictnessNames, strings.ToUpper(value)) {
+			return &diagnostics.Diagnostic{
+				Position: valueNode.Position(),
+				Err:      fmt.Errorf("invalid strictness value %q, expected one of %s", value, strings.Join(strictnessNames, ", ")),
+			}
+		}
+	case symbols.BuildKeywordToken:
+		if !equality {
+			return &diagnostics.Diagnostic{
+				Position: operatorNode.Position(),
+				Err:      fmt.Errorf("BUILD can only be compared with == and !=, got %s", operator),
+			}
+		}
+	default:
+		if _, err := strconv.Atoi(value); !equality && err != nil {
+			return &diagnostics.Diagnostic{
+				Position: valueNode.Position(),
+				Err:      fmt.Errorf("%s compares numbers, got %q", operator, value),
+			}
+		}
+	}
+	return nil
+}
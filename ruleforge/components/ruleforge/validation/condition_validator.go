@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/conditions"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// ConditionValidator type-checks every condition against the identifier registry:
+// the identifier must be registered, compared with one of its operators and given values of its kind.
+// Variable references are checked through the values of the variables, if they are known.
+type ConditionValidator struct {
+	documentTree []*shared.ParseTree[symbols.LexingTokenType]
+	variables    map[string][]string
+	evaluate     ExpressionEvaluator
+}
+
+func NewConditionValidator(documentTree []*shared.ParseTree[symbols.LexingTokenType], variables map[string][]string, evaluate ExpressionEvaluator) *ConditionValidator {
+	return &ConditionValidator{documentTree: documentTree, variables: variables, evaluate: evaluate}
+}
+
+// Validate reports every ill-typed condition together.
+func (c *ConditionValidator) Validate() error {
+	var problems diagnostics.List
+	for _, node := range c.documentTree {
+		for _, expression := range node.FindAllSymbolNodes(symbols.ParseSymbolConditionExpression.String()) {
+			if problem := c.validateExpression(expression); problem != nil {
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func (c *ConditionValidator) validateExpression(expression *shared.ParseTree[symbols.LexingTokenType]) *diagnostics.Diagnostic {
+	if len(expression.Children) < 3 {
+		return nil
+	}
+
+	// The connector is optional, so the parts are counted from the end.
+	identifierNode := expression.Children[len(expression.Children)-3]
+	operatorNode := expression.Children[len(expression.Children)-2]
+	valueNode := expression.Children[len(expression.Children)-1]
+	if identifierNode.Token == nil {
+		return nil
+	}
+
+	name := identifierNode.Token.ValueToString()
+	identifier, ok := conditions.Lookup(name)
+	if !ok {
+		return &diagnostics.Diagnostic{Position: identifierNode.Position(), Err: fmt.Errorf("unknown condition: %s", name)}
+	}
+
+	if problem := checkOperator(identifier, operatorNode); problem != nil {
+		return problem
+	}
+
+	var values []string
+	var source string
+	if valueNode.Symbol == symbols.ParseSymbolFullValueExpression.String() {
+		// Expressions produce a number, or a list for conditions taking several values.
+		if identifier.Kind != conditions.Numeric && !identifier.Kind.AllowsMultipleValues() {
+			return &diagnostics.Diagnostic{Position: valueNode.Position(), Err: fmt.Errorf("%s takes a %s, which cannot be computed by an expression", identifier.Name, identifier.Kind)}
+		}
+		if c.evaluate == nil {
+			return nil
+		}
+
+		evaluated, err := c.evaluate(valueNode, identifier.Kind == conditions.Numeric, c.variables)
+		if err != nil {
+			var diagnostic *diagnostics.Diagnostic
+			if errors.As(err, &diagnostic) {
+				return diagnostic
+			}
+			return &diagnostics.Diagnostic{Position: valueNode.Position(), Err: err}
+		}
+		if len(evaluated) == 0 {
+			return &diagnostics.Diagnostic{Position: valueNode.Position(), Err: fmt.Errorf("the expression leaves %s without values", identifier.Name)}
+		}
+		values = evaluated
+		source = " (from the expression)"
+	} else {
+		for _, literal := range conditionLiterals(valueNode) {
+			resolved, from := c.resolveValue(literal)
+			values = append(values, resolved...)
+			source += from
+		}
+	}
+	if err := identifier.CheckValueCount(len(values)); err != nil {
+		return &diagnostics.Diagnostic{Position: valueNode.Position(), Err: fmt.Errorf("%w%s", err, source)}
+	}
+	for _, value := range values {
+		if err := identifier.CheckValue(value); err != nil {
+			return &diagnostics.Diagnostic{Position: valueNode.Position(), Err: fmt.Errorf("%w%s", err, source)}
+		}
+	}
+	return nil
+}
+
+// checkOperator checks a plain operator, or a counted one like `>=2`, against the identifier.
+func checkOperator(identifier conditions.Identifier, node *shared.ParseTree[symbols.LexingTokenType]) *diagnostics.Diagnostic {
+	if node.Symbol == symbols.ParseSymbolCountedOperator.String() {
+		operator := node.Children[0].Token.ValueToString()
+		if !identifier.AllowsCountedOperator(operator) {
+			return &diagnostics.Diagnostic{Position: node.Position(), Err: fmt.Errorf("%s cannot count matching values; only mod conditions such as @has_explicit_mod take operators like >=2", identifier.Name)}
+		}
+		return nil
+	}
+
+	operator := node.Token.ValueToString()
+	if !identifier.AllowsOperator(operator) {
+		return &diagnostics.Diagnostic{Position: node.Position(), Err: fmt.Errorf("operator %s cannot be used with %s, which takes a %s; allowed operators: %v",
+			operator, identifier.Name, identifier.Kind, identifier.Operators)}
+	}
+	return nil
+}
+
+// conditionLiterals returns the value of a condition as written, or every value of a bracketed list.
+func conditionLiterals(node *shared.ParseTree[symbols.LexingTokenType]) []string {
+	if node.Symbol != symbols.ParseSymbolValueList.String() {
+		return []string{node.Token.ValueToString()}
+	}
+
+	var literals []string
+	for _, valueNode := range node.FindAllSymbolNodes(symbols.ParseSymbolValue.String()) {
+		literals = append(literals, valueNode.Token.ValueToString())
+	}
+	return literals
+}
+
+// resolveValue returns the values a condition value stands for and, for variables, a note naming the variable.
+// Unknown variables and variables referring to other variables resolve to nothing; the VariableValidator reports the former.
+func (c *ConditionValidator) resolveValue(value string) ([]string, string) {
+	if value == "" || value[0] != '$' {
+		return []string{value}, ""
+	}
+
+	resolved := make([]string, 0)
+	for _, variableValue := range c.variables[value[1:]] {
+		if variableValue == "" || variableValue[0] != '$' {
+			resolved = append(resolved, variableValue)
+		}
+	}
+	return resolved, fmt.Sprintf(" (through variable %s)", value)
+}
@@ -2,6 +2,8 @@ package validation
 
 import (
 	"fmt"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 )
@@ -10,11 +12,20 @@ type CorrectSyntaxValidator struct {
 	blocks []*shared.ParseTree[symbols.LexingTokenType]
 }
 
+// Validate reports every block that the parser could not recognize.
 func (v CorrectSyntaxValidator) Validate() error {
+	var errs diagnostics.List
 	for i, block := range v.blocks {
 		if block.Symbol == symbols.ParseSymbolAny.String() {
-			return fmt.Errorf("block (%d) has incorrect syntax (search on the value and find out why!): %q", i, block.Token.String())
+			errs = append(errs, &diagnostics.Diagnostic{
+				Position: block.Position(),
+				Err:      fmt.Errorf("block (%d) has incorrect syntax (search on the value and find out why!): %q", i, block.Token.String()),
+			})
 		}
 	}
+
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
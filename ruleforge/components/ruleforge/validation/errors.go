@@ -0,0 +1,24 @@
+package validation
+
+import (
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	lexshared "github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/lexing/shared"
+)
+
+// ValidationError is returned when a parse tree is syntactically valid but breaks the rules of the language.
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Position returns the location in the source that failed validation.
+func (e *ValidationError) Position() lexshared.Position {
+	return diagnostics.PositionOf(e.Err)
+}
@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"fmt"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 )
@@ -46,7 +47,7 @@ func (v *MetadataFieldsValidator) Validate() error {
 	if assignmentListNode == nil {
 		// If there are no assignments, and we expect some required fields, it's an error.
 		if len(v.options.RequiredFields) > 0 {
-			return fmt.Errorf("expected metadata assignments but found none")
+			return diagnostics.Errorf(v.metadataSectionNode.Position(), "expected metadata assignments but found none")
 		}
 		return nil // No assignments and no required fields, so valid.
 	}
@@ -54,19 +55,21 @@ func (v *MetadataFieldsValidator) Validate() error {
 	seenRequiredFields := map[symbols.LexingTokenType]bool{}
 	seenOptionalFields := map[symbols.LexingTokenType]bool{}
 	var actualFieldOrder []symbols.LexingTokenType // To track the order of *all* fields encountered
+	fieldNodes := map[symbols.LexingTokenType]*shared.ParseTree[symbols.LexingTokenType]{}
 
 	for _, assign := range assignmentListNode.Children {
 		// Ensure the child is an assignment node and has a key token
 		if len(assign.Children) < 1 || assign.Children[0].Token == nil {
-			return fmt.Errorf("malformed metadata assignment: missing field key")
+			return diagnostics.Errorf(assign.Position(), "malformed metadata assignment: missing field key")
 		}
 		keyTok := assign.Children[0].Token.Type
 		actualFieldOrder = append(actualFieldOrder, keyTok) // Keep track of the actual order
 
 		// Check for duplicates across all fields (required and optional)
 		if seenRequiredFields[keyTok] || seenOptionalFields[keyTok] {
-			return fmt.Errorf("duplicate metadata field '%s'", keyTok)
+			return diagnostics.Errorf(assign.Position(), "duplicate metadata field '%s'", keyTok)
 		}
+		fieldNodes[keyTok] = assign
 
 		// Determine if the field is optional or a required one
 		if _, isOptional := v.options.OptionalFields[keyTok]; isOptional {
@@ -84,7 +87,7 @@ func (v *MetadataFieldsValidator) Validate() error {
 				seenRequiredFields[keyTok] = true
 			} else {
 				// This field is neither in optional nor in required fields
-				return fmt.Errorf("unknown metadata field '%s'", keyTok)
+				return diagnostics.Errorf(assign.Position(), "unknown metadata field '%s'", keyTok)
 			}
 		}
 	}
@@ -92,7 +95,7 @@ func (v *MetadataFieldsValidator) Validate() error {
 	// 1. Validate all required fields are present
 	for _, requiredField := range v.options.RequiredFields {
 		if !seenRequiredFields[requiredField] {
-			return fmt.Errorf("missing required metadata field '%s'", requiredField)
+			return diagnostics.Errorf(v.metadataSectionNode.Position(), "missing required metadata field '%s'", requiredField)
 		}
 	}
 
@@ -107,7 +110,7 @@ func (v *MetadataFieldsValidator) Validate() error {
 					return fmt.Errorf("internal validation error: unexpected required field '%s' encountered during order check", field)
 				}
 				if field != v.options.RequiredFields[requiredIndex] {
-					return fmt.Errorf("expected '%s' at position %d, got '%s'", v.options.RequiredFields[requiredIndex], requiredIndex+1, field)
+					return diagnostics.Errorf(fieldNodes[field].Position(), "expected '%s' at position %d, got '%s'", v.options.RequiredFields[requiredIndex], requiredIndex+1, field)
 				}
 				requiredIndex++
 			}
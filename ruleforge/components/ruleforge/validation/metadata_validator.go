@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/validation/helpers"
@@ -62,17 +63,26 @@ func NewMetadataStrictnessValidator(node *shared.ParseTree[symbols.LexingTokenTy
 func (v *MetadataStrictnessValidator) Validate() error {
 	// find the assignment whose Key is STRICTNESS, then dive into its Value.Keyword token
 	assign := v.getStrictnessAssignment()
+	if assign == nil || len(assign.Children) < 3 {
+		return diagnostics.Errorf(v.metadataSectionNode.Position(), "missing STRICTNESS value")
+	}
 	valNode := assign.Children[2]
 	if !allowedStrictness[valNode.Token.Type] {
-		return fmt.Errorf("invalid strictness value %q", valNode.Token.Value)
+		return diagnostics.Errorf(valNode.Position(), "invalid strictness value %q", valNode.Token.Value)
 	}
 	return nil
 }
 
 func (v *MetadataStrictnessValidator) getStrictnessAssignment() *shared.ParseTree[symbols.LexingTokenType] {
 	assignmentList := v.metadataSectionNode.FindSymbolNode(symbols.ParseSymbolAssignments.String())
+	if assignmentList == nil {
+		return nil
+	}
 
 	for _, assign := range assignmentList.Children {
+		if len(assign.Children) == 0 || assign.Children[0].Token == nil {
+			continue
+		}
 		keyTokenType := assign.Children[0].Token.Type
 
 		if keyTokenType == symbols.StrictnessKeywordToken {
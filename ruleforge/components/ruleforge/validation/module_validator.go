@@ -0,0 +1,44 @@
+package validation
+
+import (
+	"errors"
+
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
+)
+
+// ModuleValidator checks the blocks that only belong at the start of a file:
+// a MODULE header must be the first block, and a module cannot have a METADATA block.
+type ModuleValidator struct {
+	blocks   []*shared.ParseTree[symbols.LexingTokenType]
+	isModule bool
+}
+
+func NewModuleValidator(blocks []*shared.ParseTree[symbols.LexingTokenType], isModule bool) *ModuleValidator {
+	return &ModuleValidator{blocks: blocks, isModule: isModule}
+}
+
+// Validate reports every misplaced block together.
+func (m *ModuleValidator) Validate() error {
+	var problems diagnostics.List
+	for _, block := range m.blocks {
+		switch {
+		case block.Symbol == symbols.ParseSymbolModule.String():
+			problems = append(problems, &diagnostics.Diagnostic{
+				Position: block.Position(),
+				Err:      errors.New("a MODULE header must be the first block of the file"),
+			})
+		case block.Symbol == symbols.ParseSymbolRootMetadata.String() && m.isModule:
+			problems = append(problems, &diagnostics.Diagnostic{
+				Position: block.Position(),
+				Err:      errors.New("a module cannot have a METADATA block, it is compiled as part of the scripts that import it"),
+			})
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
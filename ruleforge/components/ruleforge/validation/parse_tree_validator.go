@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"errors"
+
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 )
@@ -14,28 +16,66 @@ type ParseTreeValidator struct {
 	validators []Validator
 }
 
+// ParseTreeValidatorOptions holds what the validators need to know beyond the parse tree.
+type ParseTreeValidatorOptions struct {
+	// Variables are the script's variables by name, used to check the values that conditions refer to.
+	Variables map[string][]string
+	// EvaluateExpression computes the expressions written as condition values, e.g. `$map_start + 5`, from the variables.
+	// Without it, only the kind of value an expression produces is checked.
+	EvaluateExpression ExpressionEvaluator
+}
+
+// ExpressionEvaluator computes an expression node: a single number if numeric, a list of values otherwise.
+type ExpressionEvaluator func(expression *shared.ParseTree[symbols.LexingTokenType], numeric bool, variables map[string][]string) ([]string, error)
+
 // NewParseTreeValidator composes all your validators in one place.
 func NewParseTreeValidator(tree *shared.ParseTree[symbols.LexingTokenType]) *ParseTreeValidator {
-	metadataBlock := tree.Children[0]
+	return NewParseTreeValidatorWithOptions(tree, ParseTreeValidatorOptions{})
+}
+
+// NewParseTreeValidatorWithOptions composes all validators, configured with the given options.
+func NewParseTreeValidatorWithOptions(tree *shared.ParseTree[symbols.LexingTokenType], options ParseTreeValidatorOptions) *ParseTreeValidator {
+	if tree == nil || len(tree.Children) == 0 {
+		return &ParseTreeValidator{
+			validators: []Validator{emptyScriptValidator{}},
+		}
+	}
+
+	// A module starts with its MODULE header instead of a METADATA block.
+	firstBlock := tree.Children[0]
 	documentBlocks := tree.Children[1:]
+	isModule := firstBlock.Symbol == symbols.ParseSymbolModule.String()
 
-	return &ParseTreeValidator{
-		validators: []Validator{
-			NewMetadataDiscoveryValidator(metadataBlock),
-			CorrectSyntaxValidator{
-				blocks: documentBlocks,
-			},
-			NewSectionValidator(documentBlocks),
-			NewVariableValidator(documentBlocks),
-		},
+	var validators []Validator
+	if !isModule {
+		validators = append(validators, NewMetadataDiscoveryValidator(firstBlock))
 	}
+	validators = append(validators,
+		NewModuleValidator(documentBlocks, isModule),
+		CorrectSyntaxValidator{
+			blocks: documentBlocks,
+		},
+		NewSectionValidator(documentBlocks),
+		NewVariableValidator(documentBlocks),
+		NewConditionValidator(documentBlocks, options.Variables, options.EvaluateExpression),
+		NewCompileConditionValidator(documentBlocks),
+	)
+	return &ParseTreeValidator{validators: validators}
 }
 
+// Validate runs every validator and returns the first failure as a ValidationError.
 func (p *ParseTreeValidator) Validate() error {
 	for _, v := range p.validators {
 		if err := v.Validate(); err != nil {
-			return err
+			return &ValidationError{Err: err}
 		}
 	}
 	return nil
 }
+
+// emptyScriptValidator rejects scripts without any content.
+type emptyScriptValidator struct{}
+
+func (emptyScriptValidator) Validate() error {
+	return errors.New("script is empty: expected a METADATA block")
+}
@@ -1,7 +1,7 @@
 package validation
 
 import (
-	"fmt"
+	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/diagnostics"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/common/compiler/parsing/shared"
 	"github.com/LordMartron94/Ruleforge/ruleforge/components/ruleforge/rules/symbols"
 	"slices"
@@ -10,6 +10,7 @@ import (
 var builtInVariables = []string{
 	"Show",
 	"Hide",
+	"Continue",
 }
 
 type VariableValidator struct {
@@ -45,7 +46,7 @@ func (m *VariableValidator) Validate() error {
 			}
 
 			if !slices.Contains(knownVariables, referenceValue) {
-				return fmt.Errorf(`unknown variable: %s`, referenceValue)
+				return diagnostics.Errorf(variableReference.Position(), `unknown variable: %s`, referenceValue)
 			}
 		}
 	}
@@ -199,7 +199,10 @@ func processRuleforgeScript(
 	defer closeFile(file)
 
 	// 2) Build the compiler/file handler
-	handler := newFileHandler(file)
+	handler, err := newFileHandler(file)
+	if err != nil {
+		return fmt.Errorf("reading file: %w", err)
+	}
 
 	// 3) Lexing
 	_, err = handler.Lex()
@@ -246,7 +249,7 @@ func processRuleforgeScript(
 		return fmt.Errorf("compilation.NewCompiler: %w", err)
 	}
 
-	outputStrings, err, outputName := compiler.CompileIntoFilter()
+	outputFilters, err, outputName := compiler.CompileIntoFilter()
 
 	if err != nil {
 		return fmt.Errorf("compiler.CompileIntoFilter: %v", err)
@@ -254,11 +257,13 @@ func processRuleforgeScript(
 
 	// 7) Writing
 	for _, outputDir := range configuration.FilterOutputDirs {
-		outputFileName := filepath.Join(outputDir, outputName+".filter")
-		err = WriteLines(outputStrings, outputFileName)
+		for _, outputFilter := range outputFilters {
+			outputFileName := filepath.Join(outputDir, outputFilter.FileName(outputName))
+			err = WriteLines(outputFilter.Lines, outputFileName)
 
-		if err != nil {
-			return fmt.Errorf("writing output file: %w", err)
+			if err != nil {
+				return fmt.Errorf("writing output file: %w", err)
+			}
 		}
 	}
 
@@ -279,10 +284,10 @@ func closeFile(f *os.File) {
 	}
 }
 
-func newFileHandler(f *os.File) common_compiler.FileHandler[symbols.LexingTokenType] {
+func newFileHandler(f *os.File) (*common_compiler.FileHandler[symbols.LexingTokenType], error) {
 	lexingRules := rules.GetLexingRules()
 	parsingRules := rules.GetParsingRules()
-	return *common_compiler.NewFileHandler(f, lexingRules, parsingRules, symbols.IgnoreToken)
+	return common_compiler.NewFileHandler(f, lexingRules, parsingRules, symbols.IgnoreToken)
 }
 
 func printLexemes(lexemes []*shared.Token[symbols.LexingTokenType]) {